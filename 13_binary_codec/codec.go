@@ -0,0 +1,541 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 二进制格式：魔数 "ERB" + 版本号，之后是一个 tagRecord 编码的根 Record
+// 每个值都以 1 字节类型标签开头，整数使用 varint，字符串/字节使用 uvarint 长度前缀
+// 版本 2 起 tagRecords 的每个元素以 tagNil 或 tagRecord 开头，nil 元素解码后仍为 nil
+const (
+	binaryMagic   = "ERB"
+	binaryVersion = 2
+
+	// maxNestingDepth 限制嵌套层数，Record 以值形式自引用时无法通过指针识别循环
+	maxNestingDepth = 100
+)
+
+const (
+	tagNil byte = iota
+	tagBool
+	tagInt
+	tagInt8
+	tagInt16
+	tagInt32
+	tagInt64
+	tagUint
+	tagUint8
+	tagUint16
+	tagUint32
+	tagUint64
+	tagFloat32
+	tagFloat64
+	tagString
+	tagBytes
+	tagTime
+	tagRecord
+	tagRecords
+	tagSlice
+	tagStrings
+	tagInts
+	tagInt64s
+	tagFloat64s
+	tagMap
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+var errShortBuffer = errors.New("unexpected end of data")
+
+// MarshalRecord 将 Record 编码为紧凑的自描述二进制格式
+// 保留字段顺序、嵌套 Record、[]*Record、time.Time、[]byte 以及整数位宽
+func MarshalRecord(r *eorm.Record) ([]byte, error) {
+	if r == nil {
+		return nil, fmt.Errorf("binary record: record is nil")
+	}
+	buf := append([]byte(binaryMagic), binaryVersion)
+	return appendRecord(buf, r, "", make(map[*eorm.Record]bool))
+}
+
+// UnmarshalRecord 从 MarshalRecord 生成的二进制数据还原 Record
+func UnmarshalRecord(data []byte) (*eorm.Record, error) {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, fmt.Errorf("binary record: invalid magic header")
+	}
+	if v := data[len(binaryMagic)]; v != binaryVersion {
+		return nil, fmt.Errorf("binary record: unsupported version %d", v)
+	}
+	d := &decoder{data: data[len(binaryMagic)+1:]}
+	r, err := d.readRecord("")
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, fmt.Errorf("binary record: %d trailing bytes", len(d.data))
+	}
+	return r, nil
+}
+
+// BinaryRecord 包装 *eorm.Record，实现 encoding.BinaryMarshaler / BinaryUnmarshaler
+// 以及 gob.GobEncoder / GobDecoder，可直接放入 gob 流或二进制缓存
+type BinaryRecord struct {
+	*eorm.Record
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (b BinaryRecord) MarshalBinary() ([]byte, error) {
+	return MarshalRecord(b.Record)
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler
+func (b *BinaryRecord) UnmarshalBinary(data []byte) error {
+	r, err := UnmarshalRecord(data)
+	if err != nil {
+		return err
+	}
+	b.Record = r
+	return nil
+}
+
+// GobEncode 实现 gob.GobEncoder，与 MarshalBinary 使用同一格式
+func (b BinaryRecord) GobEncode() ([]byte, error) {
+	return b.MarshalBinary()
+}
+
+// GobDecode 实现 gob.GobDecoder
+func (b *BinaryRecord) GobDecode(data []byte) error {
+	return b.UnmarshalBinary(data)
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// isRecordValue 判断值是否为 Record 或 *Record
+// Set 会自动解引用指针，所以嵌套 Record 可能以值的形式存储
+func isRecordValue(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t == recordType || (t != nil && t.Kind() == reflect.Ptr && t.Elem() == recordType)
+}
+
+func appendUvarint(buf []byte, n int) []byte {
+	return binary.AppendUvarint(buf, uint64(n))
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, len(s))
+	return append(buf, s...)
+}
+
+func appendRecord(buf []byte, r *eorm.Record, path string, visiting map[*eorm.Record]bool) ([]byte, error) {
+	if visiting[r] || len(visiting) >= maxNestingDepth {
+		return nil, fmt.Errorf("binary record: cycle detected at '%s'", path)
+	}
+	visiting[r] = true
+	defer delete(visiting, r)
+
+	keys := r.Keys()
+	buf = appendUvarint(buf, len(keys))
+	for _, key := range keys {
+		buf = appendString(buf, key)
+		val := r.Get(key)
+		var err error
+		if isRecordValue(val) {
+			nested, _ := r.GetRecord(key)
+			buf = append(buf, tagRecord)
+			buf, err = appendRecord(buf, nested, joinPath(path, key), visiting)
+		} else {
+			buf, err = appendValue(buf, val, joinPath(path, key), visiting)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendValue(buf []byte, val interface{}, path string, visiting map[*eorm.Record]bool) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(buf, tagNil), nil
+	case bool:
+		if v {
+			return append(buf, tagBool, 1), nil
+		}
+		return append(buf, tagBool, 0), nil
+	case int:
+		return binary.AppendVarint(append(buf, tagInt), int64(v)), nil
+	case int8:
+		return binary.AppendVarint(append(buf, tagInt8), int64(v)), nil
+	case int16:
+		return binary.AppendVarint(append(buf, tagInt16), int64(v)), nil
+	case int32:
+		return binary.AppendVarint(append(buf, tagInt32), int64(v)), nil
+	case int64:
+		return binary.AppendVarint(append(buf, tagInt64), v), nil
+	case uint:
+		return binary.AppendUvarint(append(buf, tagUint), uint64(v)), nil
+	case uint8:
+		return binary.AppendUvarint(append(buf, tagUint8), uint64(v)), nil
+	case uint16:
+		return binary.AppendUvarint(append(buf, tagUint16), uint64(v)), nil
+	case uint32:
+		return binary.AppendUvarint(append(buf, tagUint32), uint64(v)), nil
+	case uint64:
+		return binary.AppendUvarint(append(buf, tagUint64), v), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(buf, tagFloat32), math.Float32bits(v)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(buf, tagFloat64), math.Float64bits(v)), nil
+	case string:
+		return appendString(append(buf, tagString), v), nil
+	case []byte:
+		buf = appendUvarint(append(buf, tagBytes), len(v))
+		return append(buf, v...), nil
+	case time.Time:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("binary record: '%s': %v", path, err)
+		}
+		buf = appendUvarint(append(buf, tagTime), len(data))
+		return append(buf, data...), nil
+	case *eorm.Record:
+		return appendRecord(append(buf, tagRecord), v, path, visiting)
+	case []*eorm.Record:
+		buf = appendUvarint(append(buf, tagRecords), len(v))
+		var err error
+		for i, item := range v {
+			if item == nil {
+				buf = append(buf, tagNil)
+				continue
+			}
+			if buf, err = appendRecord(append(buf, tagRecord), item, fmt.Sprintf("%s[%d]", path, i), visiting); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case []interface{}:
+		buf = appendUvarint(append(buf, tagSlice), len(v))
+		var err error
+		for i, item := range v {
+			if buf, err = appendValue(buf, item, fmt.Sprintf("%s[%d]", path, i), visiting); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case []string:
+		buf = appendUvarint(append(buf, tagStrings), len(v))
+		for _, s := range v {
+			buf = appendString(buf, s)
+		}
+		return buf, nil
+	case []int:
+		buf = appendUvarint(append(buf, tagInts), len(v))
+		for _, n := range v {
+			buf = binary.AppendVarint(buf, int64(n))
+		}
+		return buf, nil
+	case []int64:
+		buf = appendUvarint(append(buf, tagInt64s), len(v))
+		for _, n := range v {
+			buf = binary.AppendVarint(buf, n)
+		}
+		return buf, nil
+	case []float64:
+		buf = appendUvarint(append(buf, tagFloat64s), len(v))
+		for _, f := range v {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+		}
+		return buf, nil
+	case map[string]interface{}:
+		// map 按 Record 的方式编码，解码后成为嵌套 Record
+		buf = append(buf, tagMap)
+		return appendRecord(buf, eorm.FromMap(v), path, visiting)
+	}
+
+	if isRecordValue(val) {
+		// Record 值类型：通过临时 Record 取回指针，避免复制锁
+		holder := eorm.NewRecord().Set("v", val)
+		nested, _ := holder.GetRecord("v")
+		return appendRecord(append(buf, tagRecord), nested, path, visiting)
+	}
+	return nil, fmt.Errorf("binary record: unsupported type %T at '%s'", val, path)
+}
+
+// decodeError 记录解码失败的位置
+type decodeError struct {
+	path string
+	err  error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("binary record: '%s': %v", e.path, e.err)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if len(d.data) == 0 {
+		return 0, errShortBuffer
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b, nil
+}
+
+func (d *decoder) readVarint() (int64, error) {
+	n, size := binary.Varint(d.data)
+	if size <= 0 {
+		return 0, errShortBuffer
+	}
+	d.data = d.data[size:]
+	return n, nil
+}
+
+func (d *decoder) readUvarint() (uint64, error) {
+	n, size := binary.Uvarint(d.data)
+	if size <= 0 {
+		return 0, errShortBuffer
+	}
+	d.data = d.data[size:]
+	return n, nil
+}
+
+func (d *decoder) readLen() (int, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)) {
+		return 0, errShortBuffer
+	}
+	return int(n), nil
+}
+
+func (d *decoder) readBytes() ([]byte, error) {
+	n, err := d.readLen()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	copy(b, d.data[:n])
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *decoder) readString() (string, error) {
+	b, err := d.readBytes()
+	return string(b), err
+}
+
+func (d *decoder) readFixed(n int) ([]byte, error) {
+	if len(d.data) < n {
+		return nil, errShortBuffer
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// readRecord 读取一个 Record，出错时返回带路径的错误
+func (d *decoder) readRecord(path string) (*eorm.Record, error) {
+	count, err := d.readLen()
+	if err != nil {
+		return nil, &decodeError{path: path, err: err}
+	}
+	r := eorm.NewRecord()
+	for i := 0; i < count; i++ {
+		key, err := d.readString()
+		if err != nil {
+			return nil, &decodeError{path: path, err: err}
+		}
+		val, err := d.readValue(joinPath(path, key))
+		if err != nil {
+			return nil, err
+		}
+		r.Set(key, val)
+	}
+	return r, nil
+}
+
+// readValue 读取一个带类型标签的值，出错时返回带路径的错误
+func (d *decoder) readValue(path string) (interface{}, error) {
+	val, err := d.readTagged(path)
+	var de *decodeError
+	if err != nil && !errors.As(err, &de) {
+		err = &decodeError{path: path, err: err}
+	}
+	return val, err
+}
+
+func (d *decoder) readTagged(path string) (interface{}, error) {
+	tag, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagBool:
+		b, err := d.readByte()
+		return b == 1, err
+	case tagInt, tagInt8, tagInt16, tagInt32, tagInt64:
+		n, err := d.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagInt:
+			return int(n), nil
+		case tagInt8:
+			return int8(n), nil
+		case tagInt16:
+			return int16(n), nil
+		case tagInt32:
+			return int32(n), nil
+		}
+		return n, nil
+	case tagUint, tagUint8, tagUint16, tagUint32, tagUint64:
+		n, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagUint:
+			return uint(n), nil
+		case tagUint8:
+			return uint8(n), nil
+		case tagUint16:
+			return uint16(n), nil
+		case tagUint32:
+			return uint32(n), nil
+		}
+		return n, nil
+	case tagFloat32:
+		b, err := d.readFixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case tagFloat64:
+		b, err := d.readFixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case tagString:
+		return d.readString()
+	case tagBytes:
+		return d.readBytes()
+	case tagTime:
+		b, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		var t time.Time
+		if err := t.UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case tagRecord, tagMap:
+		return d.readRecord(path)
+	case tagRecords:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		records := make([]*eorm.Record, n)
+		for i := range records {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			elemTag, err := d.readByte()
+			if err != nil {
+				return nil, &decodeError{path: elemPath, err: err}
+			}
+			switch elemTag {
+			case tagNil:
+				continue
+			case tagRecord:
+				if records[i], err = d.readRecord(elemPath); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, &decodeError{path: elemPath, err: fmt.Errorf("unknown record tag %d", elemTag)}
+			}
+		}
+		return records, nil
+	case tagSlice:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = d.readValue(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case tagStrings:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, n)
+		for i := range items {
+			if items[i], err = d.readString(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case tagInts, tagInt64s:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]int64, n)
+		for i := range items {
+			if items[i], err = d.readVarint(); err != nil {
+				return nil, err
+			}
+		}
+		if tag == tagInt64s {
+			return items, nil
+		}
+		ints := make([]int, n)
+		for i, v := range items {
+			ints[i] = int(v)
+		}
+		return ints, nil
+	case tagFloat64s:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]float64, n)
+		for i := range items {
+			b, err := d.readFixed(8)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown type tag %d", tag)
+}
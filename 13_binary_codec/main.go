@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例13：Record 二进制编码与 gob 支持
+// 演示 MarshalRecord/UnmarshalRecord 以及 BinaryRecord 在 gob 流中的使用
+func main() {
+	fmt.Println("========== Record 二进制编码示例 ==========")
+
+	createdAt := time.Date(2024, 1, 1, 8, 30, 0, 0, time.FixedZone("CST", 8*3600))
+
+	// 1. 构造包含多种类型的 Record
+	record := eorm.NewRecord().
+		Set("id", int64(9007199254740993)).
+		Set("name", "张三").
+		Set("age", int16(25)).
+		Set("score", 98.5).
+		Set("active", true).
+		Set("avatar", []byte{0x89, 0x50, 0x4e, 0x47}).
+		Set("created_at", createdAt).
+		Set("profile", eorm.NewRecord().
+			Set("city", "北京").
+			Set("zip", uint32(100000))).
+		Set("orders", []*eorm.Record{
+			eorm.NewRecord().Set("order_id", "001").Set("amount", 100.10),
+			eorm.NewRecord().Set("order_id", "002").Set("amount", 200.20),
+		}).
+		Set("tags", []interface{}{"golang", int64(1), nil})
+	fmt.Printf("1. 原始记录: %v\n", record.ToJson())
+
+	// 2. MarshalRecord - 编码为二进制
+	data, err := MarshalRecord(record)
+	if err != nil {
+		fmt.Printf("2. 编码失败: %v\n", err)
+		return
+	}
+	fmt.Printf("2. 编码后字节数: %d (JSON 字节数: %d)\n", len(data), len(record.ToJson()))
+
+	// 3. UnmarshalRecord - 从二进制还原
+	decoded, err := UnmarshalRecord(data)
+	if err != nil {
+		fmt.Printf("3. 解码失败: %v\n", err)
+		return
+	}
+	fmt.Printf("3. 解码记录: %v\n", decoded.ToJson())
+
+	// 4. 验证类型与位宽保持不变
+	fmt.Println("\n4. 验证类型与位宽")
+	fmt.Printf("   id 类型: %T, GetInt64: %d\n", decoded.Get("id"), decoded.GetInt64("id"))
+	fmt.Printf("   age 类型: %T\n", decoded.Get("age"))
+	fmt.Printf("   avatar 类型: %T, GetBytes: %v\n", decoded.Get("avatar"), decoded.GetBytes("avatar"))
+	fmt.Printf("   created_at 类型: %T, 相等: %v\n", decoded.Get("created_at"), decoded.GetTime("created_at").Equal(createdAt))
+	zip, _ := decoded.GetRecord("profile")
+	fmt.Printf("   profile.zip 类型: %T\n", zip.Get("zip"))
+	orders, err := decoded.GetRecords("orders")
+	if err != nil {
+		fmt.Printf("   获取 orders 失败: %v\n", err)
+	} else {
+		fmt.Printf("   orders 类型: %T, 共 %d 个, 第二个金额: %v\n", decoded.Get("orders"), len(orders), orders[1].GetFloat("amount"))
+	}
+	withNil, _ := MarshalRecord(eorm.NewRecord().Set("items", []*eorm.Record{nil, eorm.NewRecord().Set("sku", "A1")}))
+	if back, err := UnmarshalRecord(withNil); err == nil {
+		items := back.Get("items").([]*eorm.Record)
+		fmt.Printf("   []*Record 中的 nil 元素: items[0]==nil: %v, items[1].sku: %s\n", items[0] == nil, items[1].GetString("sku"))
+	}
+	if decoded.ToJson() == record.ToJson() {
+		fmt.Println("   ✅ 往返编码后 JSON 输出一致")
+	} else {
+		fmt.Println("   ❌ 往返编码后 JSON 输出不一致")
+	}
+
+	// 5. 在 gob 流中使用 BinaryRecord
+	fmt.Println("\n5. 在 gob 流中使用 BinaryRecord")
+	type CacheEntry struct {
+		Key     string
+		Expires time.Time
+		Value   BinaryRecord
+	}
+
+	var buf bytes.Buffer
+	entry := CacheEntry{Key: "user:1", Expires: createdAt.Add(time.Hour), Value: BinaryRecord{record}}
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		fmt.Printf("   gob 编码失败: %v\n", err)
+		return
+	}
+
+	var restored CacheEntry
+	if err := gob.NewDecoder(&buf).Decode(&restored); err != nil {
+		fmt.Printf("   gob 解码失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   Key: %s\n", restored.Key)
+	fmt.Printf("   Value: %v\n", restored.Value.ToJson())
+	fmt.Printf("   Value.id 类型: %T\n", restored.Value.Get("id"))
+
+	// 6. 错误处理
+	fmt.Println("\n6. 错误处理")
+	if _, err := UnmarshalRecord([]byte("not a record")); err != nil {
+		fmt.Printf("   无效数据 (预期): %v\n", err)
+	}
+	if _, err := UnmarshalRecord(data[:len(data)-3]); err != nil {
+		fmt.Printf("   截断数据 (预期): %v\n", err)
+	}
+	type unsupported struct{ X int }
+	if _, err := MarshalRecord(eorm.NewRecord().Set("meta", eorm.NewRecord().Set("bad", unsupported{1}))); err != nil {
+		fmt.Printf("   不支持的类型 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
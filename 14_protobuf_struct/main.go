@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 示例14：Record 与 protobuf 互转
+// 演示 FromProtoStruct、ToProtoStruct 以及通过 protobuf 反射解码任意消息的 FromProtoMessage
+func main() {
+	fmt.Println("========== Record 与 protobuf 互转示例 ==========")
+
+	// 1. FromProtoStruct - 从 google.protobuf.Struct 创建 Record
+	fmt.Println("\n1. FromProtoStruct - 从 google.protobuf.Struct 创建 Record")
+	payload, err := structpb.NewStruct(map[string]interface{}{
+		"username": "zhangsan",
+		"age":      25,
+		"profile": map[string]interface{}{
+			"city": "北京",
+		},
+		"orders": []interface{}{
+			map[string]interface{}{"order_id": "001", "amount": 100.5},
+			map[string]interface{}{"order_id": "002", "amount": 200},
+		},
+		"tags": []interface{}{"golang", "grpc"},
+	})
+	if err != nil {
+		fmt.Printf("   创建 Struct 失败: %v\n", err)
+		return
+	}
+	record := FromProtoStruct(payload)
+	fmt.Printf("   Record: %v\n", record.ToJson())
+	city, _ := record.GetStringByPath("profile.city")
+	fmt.Printf("   profile.city: %s\n", city)
+	orders, _ := record.GetRecords("orders")
+	fmt.Printf("   orders: 共 %d 个，第一个订单号: %s\n", len(orders), orders[0].GetString("order_id"))
+
+	// 2. ToProtoStruct - 将 Record 转换为 google.protobuf.Struct
+	fmt.Println("\n2. ToProtoStruct - 将 Record 转换为 google.protobuf.Struct")
+	response := eorm.NewRecord().
+		Set("code", 0).
+		Set("message", "ok").
+		Set("timestamp", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+		Set("data", record)
+	pbStruct, err := ToProtoStruct(response)
+	if err != nil {
+		fmt.Printf("   转换失败: %v\n", err)
+	} else {
+		data, _ := protojson.Marshal(pbStruct)
+		fmt.Printf("   Struct: %s\n", data)
+		fmt.Printf("   timestamp 字段: %s\n", pbStruct.GetFields()["timestamp"].GetStringValue())
+	}
+
+	// 3. 往返转换
+	fmt.Println("\n3. Struct -> Record -> Struct 往返转换")
+	back, err := ToProtoStruct(record)
+	if err != nil {
+		fmt.Printf("   转换失败: %v\n", err)
+	} else if proto.Equal(back, payload) {
+		fmt.Println("   ✅ 往返后与原始 Struct 相等")
+	} else {
+		fmt.Println("   ❌ 往返后与原始 Struct 不相等")
+	}
+
+	// 4. FromProtoMessage - 通过反射解码任意消息
+	fmt.Println("\n4. FromProtoMessage - 通过反射解码任意消息")
+	user, err := newUserMessage()
+	if err != nil {
+		fmt.Printf("   构造消息失败: %v\n", err)
+		return
+	}
+	userRecord, err := FromProtoMessage(user)
+	if err != nil {
+		fmt.Printf("   解码失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   Record: %v\n", userRecord.ToJson())
+	fmt.Printf("   id 类型: %T, age 类型: %T, created_at 类型: %T\n",
+		userRecord.Get("id"), userRecord.Get("age"), userRecord.Get("created_at"))
+	fmt.Printf("   status: %s\n", userRecord.GetString("status"))
+	street, _ := userRecord.GetStringByPath("address.street")
+	fmt.Printf("   address.street: %s\n", street)
+
+	// 5. gRPC handler 中的用法：解码后直接写入数据库
+	fmt.Println("\n5. gRPC handler 中的用法")
+	fmt.Println(`   userRecord, err := FromProtoMessage(req)
+   if err != nil {
+       return nil, status.Error(codes.InvalidArgument, err.Error())
+   }
+   userRecord.Remove("address")
+   userRecord.Remove("labels")
+   _, err = eorm.InsertRecord("users", userRecord)`)
+
+	// 6. 错误处理
+	fmt.Println("\n6. 错误处理")
+	if _, err := ToProtoStruct(eorm.NewRecord().Set("ch", make(chan int))); err != nil {
+		fmt.Printf("   不支持的类型 (预期): %v\n", err)
+	}
+	if _, err := ToProtoStruct(eorm.NewRecord().Set("order_id", int64(9007199254740993))); err != nil {
+		fmt.Printf("   整数超出 ±2^53 (预期): %v\n", err)
+	}
+	if _, err := FromProtoMessage(nil); err != nil {
+		fmt.Printf("   nil 消息 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+// newUserMessage 使用动态描述符构造一个 User 消息，等价于：
+//
+//	message User {
+//	  enum Status { UNKNOWN = 0; ACTIVE = 1; DISABLED = 2; }
+//	  message Address { string city = 1; string street = 2; }
+//	  int64 id = 1;
+//	  string name = 2;
+//	  int32 age = 3;
+//	  Status status = 4;
+//	  repeated string tags = 5;
+//	  Address address = 6;
+//	  google.protobuf.Timestamp created_at = 7;
+//	  map<string, string> labels = 8;
+//	}
+//
+// 实际项目中直接传入 protoc 生成的消息即可
+func newUserMessage() (proto.Message, error) {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label *descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label,
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/user.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("age", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+				field("status", 4, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".example.User.Status"),
+				field("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+				field("address", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".example.User.Address"),
+				field("created_at", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+				field("labels", 8, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".example.User.LabelsEntry"),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Address"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("street", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					},
+				},
+				{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name: proto.String("Status"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+					{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+					{Name: proto.String("DISABLED"), Number: proto.Int32(2)},
+				},
+			}},
+		}},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, err
+	}
+	desc := fd.Messages().ByName("User")
+	msg := dynamicpb.NewMessage(desc)
+	fields := desc.Fields()
+
+	msg.Set(fields.ByName("id"), protoreflect.ValueOfInt64(10001))
+	msg.Set(fields.ByName("name"), protoreflect.ValueOfString("张三"))
+	msg.Set(fields.ByName("age"), protoreflect.ValueOfInt32(25))
+	msg.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+
+	tags := msg.Mutable(fields.ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("vip"))
+	tags.Append(protoreflect.ValueOfString("beta"))
+
+	address := msg.Mutable(fields.ByName("address")).Message()
+	address.Set(desc.Messages().ByName("Address").Fields().ByName("city"), protoreflect.ValueOfString("北京"))
+	address.Set(desc.Messages().ByName("Address").Fields().ByName("street"), protoreflect.ValueOfString("长安街 1 号"))
+
+	createdAt := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tsMsg := msg.Mutable(fields.ByName("created_at")).Message()
+	proto.Merge(tsMsg.Interface(), createdAt)
+
+	labels := msg.Mutable(fields.ByName("labels")).Map()
+	labels.Set(protoreflect.ValueOfString("source").MapKey(), protoreflect.ValueOfString("app"))
+
+	return msg, nil
+}
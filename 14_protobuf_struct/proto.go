@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// maxExactInt 是 float64 能精确表示的最大整数 2^53
+const maxExactInt = 1 << 53
+
+// FromProtoStruct 将 google.protobuf.Struct 转换为 Record
+// 嵌套 Struct 转为嵌套 Record，元素全部为 Struct 的 ListValue 转为 []*Record
+// Struct 本身没有字段顺序，这里按键名排序以保证输出稳定
+func FromProtoStruct(s *structpb.Struct) *eorm.Record {
+	record := eorm.NewRecord()
+	if s == nil {
+		return record
+	}
+	keys := make([]string, 0, len(s.GetFields()))
+	for k := range s.GetFields() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		record.Set(k, fromProtoValue(s.GetFields()[k]))
+	}
+	return record
+}
+
+func fromProtoValue(v *structpb.Value) interface{} {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		return kind.StringValue
+	case *structpb.Value_NumberValue:
+		return kind.NumberValue
+	case *structpb.Value_BoolValue:
+		return kind.BoolValue
+	case *structpb.Value_StructValue:
+		return FromProtoStruct(kind.StructValue)
+	case *structpb.Value_ListValue:
+		values := kind.ListValue.GetValues()
+		if len(values) > 0 && allStructs(values) {
+			records := make([]*eorm.Record, len(values))
+			for i, item := range values {
+				records[i] = FromProtoStruct(item.GetStructValue())
+			}
+			return records
+		}
+		items := make([]interface{}, len(values))
+		for i, item := range values {
+			items[i] = fromProtoValue(item)
+		}
+		return items
+	}
+	// NullValue 或未设置
+	return nil
+}
+
+func allStructs(values []*structpb.Value) bool {
+	for _, v := range values {
+		if _, ok := v.GetKind().(*structpb.Value_StructValue); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ToProtoStruct 将 Record 转换为 google.protobuf.Struct
+// 数值统一转为 number，time.Time 转为 RFC3339 字符串，[]byte 转为 base64 字符串（与 ToJson 一致）
+// number 是 float64，绝对值超过 2^53 的整数无法精确表示，返回错误而不是静默舍入
+func ToProtoStruct(r *eorm.Record) (*structpb.Struct, error) {
+	if r == nil {
+		return nil, fmt.Errorf("proto struct: record is nil")
+	}
+	return toProtoStruct(r, "")
+}
+
+func toProtoStruct(r *eorm.Record, path string) (*structpb.Struct, error) {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(r.Keys()))}
+	for _, key := range r.Keys() {
+		val := r.Get(key)
+		if isRecordValue(val) {
+			val, _ = r.GetRecord(key)
+		}
+		pv, err := toProtoValue(val, joinPath(path, key))
+		if err != nil {
+			return nil, err
+		}
+		s.Fields[key] = pv
+	}
+	return s, nil
+}
+
+func toProtoValue(val interface{}, path string) (*structpb.Value, error) {
+	switch v := val.(type) {
+	case nil:
+		return structpb.NewNullValue(), nil
+	case *eorm.Record:
+		s, err := toProtoStruct(v, path)
+		if err != nil {
+			return nil, err
+		}
+		return structpb.NewStructValue(s), nil
+	case []*eorm.Record:
+		list := &structpb.ListValue{Values: make([]*structpb.Value, len(v))}
+		for i, item := range v {
+			pv, err := toProtoValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			list.Values[i] = pv
+		}
+		return structpb.NewListValue(list), nil
+	case time.Time:
+		return structpb.NewStringValue(v.Format(time.RFC3339Nano)), nil
+	case time.Duration:
+		return structpb.NewStringValue(v.String()), nil
+	case []byte:
+		return structpb.NewStringValue(base64.StdEncoding.EncodeToString(v)), nil
+	case string:
+		return structpb.NewStringValue(v), nil
+	case bool:
+		return structpb.NewBoolValue(v), nil
+	}
+
+	if isRecordValue(val) {
+		holder := eorm.NewRecord().Set("v", val)
+		nested, _ := holder.GetRecord("v")
+		return toProtoValue(nested, path)
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := rv.Int(); n > maxExactInt || n < -maxExactInt {
+			return nil, fmt.Errorf("proto struct: integer %d at '%s' exceeds ±2^53 and cannot be represented exactly as a number", n, path)
+		}
+		return structpb.NewNumberValue(float64(rv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := rv.Uint(); n > maxExactInt {
+			return nil, fmt.Errorf("proto struct: integer %d at '%s' exceeds ±2^53 and cannot be represented exactly as a number", n, path)
+		}
+		return structpb.NewNumberValue(float64(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return structpb.NewNumberValue(rv.Float()), nil
+	case reflect.Slice, reflect.Array:
+		list := &structpb.ListValue{Values: make([]*structpb.Value, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			pv, err := toProtoValue(rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			list.Values[i] = pv
+		}
+		return structpb.NewListValue(list), nil
+	case reflect.Map:
+		if m, ok := val.(map[string]interface{}); ok {
+			return toProtoValue(eorm.FromMap(m), path)
+		}
+	}
+	return nil, fmt.Errorf("proto struct: unsupported type %T at '%s'", val, path)
+}
+
+// FromProtoMessage 通过 protobuf 反射将任意 proto.Message 解码为 Record
+// 键名使用 proto 字段名（通常为 snake_case，可直接对应数据库列名），字段顺序与 .proto 定义一致
+// 规则：
+// 1. 标量字段保留位宽（int32、int64、uint32、float32 等），枚举转为枚举名
+// 2. 嵌套消息转为嵌套 Record，repeated 消息转为 []*Record，map 转为 Record
+// 3. Timestamp 转为 time.Time，Duration 转为 time.Duration，Struct/Value 与包装类型展开为普通值
+// 4. 未设置的消息字段与 oneof 分支不会出现在 Record 中，标量字段即使为零值也会输出
+func FromProtoMessage(m proto.Message) (*eorm.Record, error) {
+	if m == nil {
+		return nil, fmt.Errorf("proto message: message is nil")
+	}
+	msg := m.ProtoReflect()
+	if !msg.IsValid() {
+		return eorm.NewRecord(), nil
+	}
+	return fromMessage(msg)
+}
+
+func fromMessage(msg protoreflect.Message) (*eorm.Record, error) {
+	record := eorm.NewRecord()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) && (fd.ContainingOneof() != nil || (fd.Message() != nil && !fd.IsList() && !fd.IsMap())) {
+			continue
+		}
+		val, err := fromField(fd, msg.Get(fd))
+		if err != nil {
+			return nil, fmt.Errorf("proto message: field '%s': %w", fd.FullName(), err)
+		}
+		record.Set(string(fd.Name()), val)
+	}
+	return record, nil
+}
+
+func fromField(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch {
+	case fd.IsMap():
+		record := eorm.NewRecord()
+		var err error
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			var item interface{}
+			item, err = fromSingular(fd.MapValue(), mv)
+			record.Set(k.String(), item)
+			return err == nil
+		})
+		return record, err
+	case fd.IsList():
+		list := v.List()
+		if fd.Message() != nil && !isWellKnown(fd.Message().FullName()) {
+			records := make([]*eorm.Record, list.Len())
+			for i := range records {
+				r, err := fromMessage(list.Get(i).Message())
+				if err != nil {
+					return nil, err
+				}
+				records[i] = r
+			}
+			return records, nil
+		}
+		items := make([]interface{}, list.Len())
+		for i := range items {
+			item, err := fromSingular(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return fromSingular(fd, v)
+}
+
+func fromSingular(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool(), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int32(v.Int()), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return uint32(v.Uint()), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), nil
+	case protoreflect.FloatKind:
+		return float32(v.Float()), nil
+	case protoreflect.DoubleKind:
+		return v.Float(), nil
+	case protoreflect.StringKind:
+		return v.String(), nil
+	case protoreflect.BytesKind:
+		return v.Bytes(), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), nil
+		}
+		return int32(v.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return fromWellKnown(v.Message())
+	}
+	return nil, fmt.Errorf("unsupported kind %s", fd.Kind())
+}
+
+func isWellKnown(name protoreflect.FullName) bool {
+	switch name {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration",
+		"google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.ListValue",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue",
+		"google.protobuf.Int32Value", "google.protobuf.Int64Value",
+		"google.protobuf.UInt32Value", "google.protobuf.UInt64Value",
+		"google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return true
+	}
+	return false
+}
+
+// fromWellKnown 展开常用的 well-known 类型，其余消息转为嵌套 Record
+func fromWellKnown(msg protoreflect.Message) (interface{}, error) {
+	name := msg.Descriptor().FullName()
+	if !isWellKnown(name) {
+		return fromMessage(msg)
+	}
+
+	switch name {
+	case "google.protobuf.Timestamp":
+		ts := &timestamppb.Timestamp{}
+		proto.Merge(ts, msg.Interface())
+		return ts.AsTime(), nil
+	case "google.protobuf.Duration":
+		d := &durationpb.Duration{}
+		proto.Merge(d, msg.Interface())
+		return d.AsDuration(), nil
+	case "google.protobuf.Struct":
+		s := &structpb.Struct{}
+		proto.Merge(s, msg.Interface())
+		return FromProtoStruct(s), nil
+	case "google.protobuf.Value":
+		pv := &structpb.Value{}
+		proto.Merge(pv, msg.Interface())
+		return fromProtoValue(pv), nil
+	case "google.protobuf.ListValue":
+		lv := &structpb.ListValue{}
+		proto.Merge(lv, msg.Interface())
+		return fromProtoValue(structpb.NewListValue(lv)), nil
+	}
+
+	// 包装类型（Int64Value 等）只有一个名为 value 的字段
+	fd := msg.Descriptor().Fields().ByName("value")
+	return fromSingular(fd, msg.Get(fd))
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// isRecordValue 判断值是否为 Record 值类型（Set 会自动解引用 *Record）
+func isRecordValue(v interface{}) bool {
+	return reflect.TypeOf(v) == recordType
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// defaultMaxMemory 与 net/http 中 ParseMultipartForm 的默认值保持一致
+const defaultMaxMemory = 32 << 20
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// ValuesStyle 决定 ToValues 输出嵌套键名的方式
+type ValuesStyle int
+
+const (
+	// BracketStyle 输出 profile[city]、tags[]、orders[0][id]
+	BracketStyle ValuesStyle = iota
+	// DotStyle 输出 profile.city、tags（重复键）、orders.0.id
+	DotStyle
+)
+
+// formNode 是解析表单键时使用的中间树
+// Record 嵌套时以值的形式保存，子 Record 必须先构建完成再 Set 到父 Record，所以不能边解析边写入
+type formNode struct {
+	keys     []string
+	children map[string]*formNode
+	values   []string
+	files    []*multipart.FileHeader
+	isList   bool // 键名以 [] 结尾，显式声明为数组
+}
+
+func newFormNode() *formNode {
+	return &formNode{children: make(map[string]*formNode)}
+}
+
+func (n *formNode) child(key string) *formNode {
+	if c, ok := n.children[key]; ok {
+		return c
+	}
+	c := newFormNode()
+	n.children[key] = c
+	n.keys = append(n.keys, key)
+	return c
+}
+
+// FromValues 将 url.Values 转换为 Record
+// 支持的键名写法：
+// 1. 方括号嵌套：profile[city]=北京
+// 2. 点分嵌套：profile.city=北京
+// 3. 数组：tags[]=a&tags[]=b，或重复键 tags=a&tags=b
+// 4. 下标数组：orders[0][id]=1&orders[1][id]=2，元素都是对象时转为 []*Record
+// 所有叶子值保持字符串类型，读取时使用 GetInt、GetBool 等方法转换
+func FromValues(values url.Values) (*eorm.Record, error) {
+	root := newFormNode()
+	if err := addValues(root, values); err != nil {
+		return nil, err
+	}
+	return buildRecord(root, "")
+}
+
+// ToValues 将 Record 转换为 url.Values，是 FromValues 的逆操作
+func ToValues(r *eorm.Record, style ValuesStyle) url.Values {
+	values := url.Values{}
+	if r != nil {
+		writeRecordValues(values, r, "", style)
+	}
+	return values
+}
+
+// FromRequest 根据 Content-Type 从 HTTP 请求创建 Record
+// 1. application/json：解析请求体 JSON，请求体最多读取 defaultMaxMemory 字节
+// 2. multipart/form-data：解析表单字段和查询参数，上传文件以 []*multipart.FileHeader 保存
+// 3. 其他（包括 application/x-www-form-urlencoded 和 GET 请求）：解析表单字段和查询参数
+func FromRequest(req *http.Request) (*eorm.Record, error) {
+	mediaType := ""
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+			return nil, fmt.Errorf("form: invalid Content-Type %q: %v", ct, err)
+		}
+	}
+
+	switch mediaType {
+	case "application/json":
+		// 多读一个字节，用于判断请求体是否超过上限
+		body, err := io.ReadAll(io.LimitReader(req.Body, defaultMaxMemory+1))
+		if err != nil {
+			return nil, fmt.Errorf("form: read body: %v", err)
+		}
+		if len(body) > defaultMaxMemory {
+			return nil, fmt.Errorf("form: request body exceeds %d bytes", defaultMaxMemory)
+		}
+		trimmed := strings.TrimSpace(string(body))
+		if len(trimmed) == 0 {
+			return eorm.NewRecord(), nil
+		}
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("form: request body is not valid JSON")
+		}
+		// FromJson 对数组等非对象的 JSON 会静默返回空 Record
+		if trimmed[0] != '{' {
+			return nil, fmt.Errorf("form: request body must be a JSON object")
+		}
+		return eorm.NewRecord().FromJson(string(body)), nil
+	case "multipart/form-data":
+		if err := req.ParseMultipartForm(defaultMaxMemory); err != nil {
+			return nil, fmt.Errorf("form: parse multipart: %v", err)
+		}
+		root := newFormNode()
+		if err := addValues(root, req.Form); err != nil {
+			return nil, err
+		}
+		fileKeys := make([]string, 0, len(req.MultipartForm.File))
+		for key := range req.MultipartForm.File {
+			fileKeys = append(fileKeys, key)
+		}
+		sort.Strings(fileKeys)
+		for _, key := range fileKeys {
+			segments, err := parseKey(key)
+			if err != nil {
+				return nil, err
+			}
+			node := root
+			for _, seg := range segments {
+				if seg != "" {
+					node = node.child(seg)
+				}
+			}
+			node.files = append(node.files, req.MultipartForm.File[key]...)
+		}
+		return buildRecord(root, "")
+	default:
+		if err := req.ParseForm(); err != nil {
+			return nil, fmt.Errorf("form: parse form: %v", err)
+		}
+		return FromValues(req.Form)
+	}
+}
+
+// addValues 将 url.Values 写入中间树，键名排序后处理以保证结果稳定
+func addValues(root *formNode, values url.Values) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		segments, err := parseKey(key)
+		if err != nil {
+			return err
+		}
+		node := root
+		for i, seg := range segments {
+			if seg == "" {
+				if i != len(segments)-1 {
+					return fmt.Errorf("form: key '%s': [] is only allowed at the end", key)
+				}
+				node.isList = true
+				break
+			}
+			node = node.child(seg)
+		}
+		node.values = append(node.values, values[key]...)
+	}
+	return nil
+}
+
+// parseKey 将 profile[city]、profile.city、tags[] 拆分为路径片段
+// 空片段表示 []
+func parseKey(key string) ([]string, error) {
+	var segments []string
+	var cur strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '.':
+			if cur.Len() == 0 && (i == 0 || key[i-1] != ']') {
+				return nil, fmt.Errorf("form: invalid key '%s'", key)
+			}
+			if cur.Len() > 0 {
+				segments = append(segments, cur.String())
+				cur.Reset()
+			}
+		case '[':
+			end := strings.IndexByte(key[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("form: unclosed '[' in key '%s'", key)
+			}
+			if cur.Len() > 0 {
+				segments = append(segments, cur.String())
+				cur.Reset()
+			} else if i == 0 {
+				return nil, fmt.Errorf("form: invalid key '%s'", key)
+			}
+			segments = append(segments, key[i+1:i+end])
+			i += end
+		default:
+			cur.WriteByte(key[i])
+		}
+	}
+	if cur.Len() > 0 {
+		segments = append(segments, cur.String())
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("form: invalid key '%s'", key)
+	}
+	return segments, nil
+}
+
+func buildRecord(n *formNode, path string) (*eorm.Record, error) {
+	record := eorm.NewRecord()
+	for _, key := range n.keys {
+		val, err := buildValue(n.children[key], joinPath(path, key))
+		if err != nil {
+			return nil, err
+		}
+		record.Set(key, val)
+	}
+	return record, nil
+}
+
+func buildValue(n *formNode, path string) (interface{}, error) {
+	if len(n.keys) > 0 {
+		if len(n.values) > 0 || len(n.files) > 0 {
+			return nil, fmt.Errorf("form: key '%s' has both a value and nested fields", path)
+		}
+		if indexes, ok := listIndexes(n); ok {
+			return buildList(n, indexes, path)
+		}
+		return buildRecord(n, path)
+	}
+
+	if len(n.files) > 0 {
+		return n.files, nil
+	}
+	if n.isList || len(n.values) > 1 {
+		items := make([]interface{}, len(n.values))
+		for i, v := range n.values {
+			items[i] = v
+		}
+		return items, nil
+	}
+	if len(n.values) == 1 {
+		return n.values[0], nil
+	}
+	return "", nil
+}
+
+// listIndexes 判断子节点是否为从 0 开始的连续下标，是则按数组处理
+func listIndexes(n *formNode) ([]int, bool) {
+	indexes := make([]int, 0, len(n.keys))
+	for _, key := range n.keys {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return nil, false
+		}
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for i, idx := range indexes {
+		if i != idx {
+			return nil, false
+		}
+	}
+	return indexes, true
+}
+
+func buildList(n *formNode, indexes []int, path string) (interface{}, error) {
+	items := make([]interface{}, len(indexes))
+	allRecords := true
+	for _, idx := range indexes {
+		key := strconv.Itoa(idx)
+		val, err := buildValue(n.children[key], fmt.Sprintf("%s[%d]", path, idx))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := val.(*eorm.Record); !ok {
+			allRecords = false
+		}
+		items[idx] = val
+	}
+	if !allRecords {
+		return items, nil
+	}
+	records := make([]*eorm.Record, len(items))
+	for i, item := range items {
+		records[i] = item.(*eorm.Record)
+	}
+	return records, nil
+}
+
+func writeRecordValues(values url.Values, r *eorm.Record, prefix string, style ValuesStyle) {
+	for _, key := range r.Keys() {
+		val := r.Get(key)
+		if reflect.TypeOf(val) == recordType {
+			val, _ = r.GetRecord(key)
+		}
+		writeValue(values, nestKey(prefix, key, style), val, style)
+	}
+}
+
+func writeValue(values url.Values, key string, val interface{}, style ValuesStyle) {
+	switch v := val.(type) {
+	case nil:
+		values.Add(key, "")
+	case *eorm.Record:
+		writeRecordValues(values, v, key, style)
+	case []*eorm.Record:
+		for i, item := range v {
+			writeRecordValues(values, item, nestKey(key, strconv.Itoa(i), style), style)
+		}
+	case []byte:
+		values.Add(key, string(v))
+	case time.Time:
+		values.Add(key, v.Format(time.RFC3339))
+	case string:
+		values.Add(key, v)
+	default:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			values.Add(key, eorm.Convert.ToString(val))
+			return
+		}
+		listKey := key
+		if style == BracketStyle {
+			listKey = key + "[]"
+		}
+		for i := 0; i < rv.Len(); i++ {
+			item := rv.Index(i).Interface()
+			if rec, ok := item.(*eorm.Record); ok {
+				writeRecordValues(values, rec, nestKey(key, strconv.Itoa(i), style), style)
+				continue
+			}
+			values.Add(listKey, eorm.Convert.ToString(item))
+		}
+	}
+}
+
+func nestKey(prefix, key string, style ValuesStyle) string {
+	if prefix == "" {
+		return key
+	}
+	if style == DotStyle {
+		return prefix + "." + key
+	}
+	return prefix + "[" + key + "]"
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
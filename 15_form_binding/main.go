@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例15：表单与查询参数绑定
+// 演示 FromValues、ToValues 以及根据 Content-Type 自动选择解析方式的 FromRequest
+func main() {
+	fmt.Println("========== Record 表单绑定示例 ==========")
+
+	// 1. FromValues - 方括号嵌套与数组
+	fmt.Println("\n1. FromValues - 方括号嵌套与数组")
+	query, _ := url.ParseQuery("username=zhangsan&profile[city]=北京&profile[age]=25&tags[]=a&tags[]=b")
+	record, err := FromValues(query)
+	if err != nil {
+		fmt.Printf("   解析失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   Record: %v\n", record.ToJson())
+	city, _ := record.GetStringByPath("profile.city")
+	profile, _ := record.GetRecord("profile")
+	tags, _ := record.GetStringSlice("tags")
+	fmt.Printf("   profile.city: %s, profile.age: %d, tags: %v\n", city, profile.GetInt("age"), tags)
+
+	// 2. FromValues - 点分嵌套、重复键和下标数组
+	fmt.Println("\n2. FromValues - 点分嵌套、重复键和下标数组")
+	form := url.Values{
+		"settings.theme":       {"dark"},
+		"settings.notify.mail": {"true"},
+		"role":                 {"admin", "editor"},
+		"orders[0][order_id]":  {"001"},
+		"orders[0][amount]":    {"100.10"},
+		"orders[1][order_id]":  {"002"},
+		"orders[1][amount]":    {"200.20"},
+	}
+	record2, err := FromValues(form)
+	if err != nil {
+		fmt.Printf("   解析失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   Record: %v\n", record2.ToJson())
+	notify, _ := record2.GetRecordByPath("settings.notify")
+	fmt.Printf("   settings.notify.mail: %v\n", notify.GetBool("mail"))
+	orders, _ := record2.GetRecords("orders")
+	fmt.Printf("   orders: 共 %d 个，第二个金额: %.2f\n", len(orders), orders[1].GetFloat("amount"))
+
+	// 3. ToValues - 转换回 url.Values
+	fmt.Println("\n3. ToValues - 转换回 url.Values")
+	fmt.Printf("   方括号风格: %s\n", decode(ToValues(record2, BracketStyle).Encode()))
+	fmt.Printf("   点分风格: %s\n", decode(ToValues(record2, DotStyle).Encode()))
+
+	roundTrip, _ := FromValues(ToValues(record2, BracketStyle))
+	if roundTrip.ToJson() == record2.ToJson() {
+		fmt.Println("   ✅ FromValues(ToValues(r)) 与原记录一致")
+	} else {
+		fmt.Printf("   ❌ 往返结果不一致: %v\n", roundTrip.ToJson())
+	}
+
+	// 4. FromRequest - JSON 请求
+	fmt.Println("\n4. FromRequest - JSON 请求")
+	jsonReq := httptest.NewRequest(http.MethodPost, "/register",
+		strings.NewReader(`{"username":"zhangsan","password":"123456","profile":{"age":25,"city":"北京"}}`))
+	jsonReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	printRequest(jsonReq)
+
+	// 5. FromRequest - 表单请求（查询参数与表单字段合并）
+	fmt.Println("\n5. FromRequest - 表单请求")
+	formReq := httptest.NewRequest(http.MethodPost, "/register?source=web",
+		strings.NewReader("username=lisi&password=123456&profile[city]=上海&tags[]=vip"))
+	formReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	printRequest(formReq)
+
+	// 6. FromRequest - multipart 请求（含文件上传）
+	fmt.Println("\n6. FromRequest - multipart 请求")
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("username", "wangwu")
+	_ = writer.WriteField("profile[city]", "广州")
+	fileWriter, _ := writer.CreateFormFile("avatar", "avatar.png")
+	_, _ = fileWriter.Write([]byte("fake png data"))
+	_ = writer.Close()
+	multipartReq := httptest.NewRequest(http.MethodPost, "/register", &body)
+	multipartReq.Header.Set("Content-Type", writer.FormDataContentType())
+	if rec := printRequest(multipartReq); rec != nil {
+		if files, ok := rec.Get("avatar").([]*multipart.FileHeader); ok {
+			fmt.Printf("   avatar: %s (%d 字节)\n", files[0].Filename, files[0].Size)
+		}
+	}
+
+	// 7. FromRequest - GET 查询参数
+	fmt.Println("\n7. FromRequest - GET 查询参数")
+	printRequest(httptest.NewRequest(http.MethodGet, "/users?page=1&size=20&filter[status]=1", nil))
+
+	// 8. 错误处理
+	fmt.Println("\n8. 错误处理")
+	if _, err := FromValues(url.Values{"a": {"1"}, "a[b]": {"2"}}); err != nil {
+		fmt.Printf("   键冲突 (预期): %v\n", err)
+	}
+	if _, err := FromValues(url.Values{"a[b": {"1"}}); err != nil {
+		fmt.Printf("   方括号未闭合 (预期): %v\n", err)
+	}
+	badReq := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{not valid json}`))
+	badReq.Header.Set("Content-Type", "application/json")
+	if _, err := FromRequest(badReq); err != nil {
+		fmt.Printf("   无效 JSON (预期): %v\n", err)
+	}
+	arrayReq := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`[{"name": "张三"}]`))
+	arrayReq.Header.Set("Content-Type", "application/json")
+	if _, err := FromRequest(arrayReq); err != nil {
+		fmt.Printf("   JSON 数组 (预期): %v\n", err)
+	}
+	hugeReq := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"bio": "`+strings.Repeat("x", defaultMaxMemory)+`"}`))
+	hugeReq.Header.Set("Content-Type", "application/json")
+	if _, err := FromRequest(hugeReq); err != nil {
+		fmt.Printf("   请求体过大 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+func printRequest(req *http.Request) *eorm.Record {
+	record, err := FromRequest(req)
+	if err != nil {
+		fmt.Printf("   解析失败: %v\n", err)
+		return nil
+	}
+	fmt.Printf("   Record: %v\n", record.ToJson())
+	if record.Has("username") {
+		fmt.Printf("   username: %s\n", record.GetString("username"))
+	}
+	return record
+}
+
+func decode(encoded string) string {
+	s, err := url.QueryUnescape(encoded)
+	if err != nil {
+		return encoded
+	}
+	return s
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// EnvOptions 控制 FromEnv 的行为
+type EnvOptions struct {
+	// Base 基础配置，环境变量按路径递归覆盖到它的深拷贝上，Base 本身不会被修改
+	Base *eorm.Record
+	// Separator 嵌套层级分隔符，默认 "__"，APP_DATABASE__HOST 对应 database.host
+	Separator string
+	// Environ 环境变量列表，格式与 os.Environ() 相同，为空时读取 os.Environ()
+	Environ []string
+}
+
+// Override 记录一次环境变量覆盖
+type Override struct {
+	Path   string      // 点分路径，如 database.host
+	EnvVar string      // 环境变量名，如 APP_DATABASE__HOST
+	Old    interface{} // 覆盖前的值，基础配置中不存在时为 nil
+	New    interface{} // 转换后的新值
+}
+
+type envEntry struct {
+	segments []string
+	name     string
+	raw      string
+}
+
+// FromEnv 读取带前缀的环境变量并覆盖到基础配置上
+// 1. 前缀后的部分按 Separator 拆分为路径，各段转为小写；基础配置中已有的键沿用原键名（大小写不敏感匹配）
+// 2. 基础配置中已有的值决定类型转换方式：int、float、bool、time.Time、time.Duration、切片、嵌套 Record
+// 3. 基础配置中不存在的路径按字符串新增
+// 返回覆盖后的新 Record 以及按路径排序的覆盖列表
+func FromEnv(prefix string, opts EnvOptions) (*eorm.Record, []Override, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = "__"
+	}
+	environ := opts.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+
+	var entries []envEntry
+	for _, kv := range environ {
+		name, raw, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		segments := strings.Split(strings.ToLower(name[len(prefix):]), strings.ToLower(sep))
+		valid := true
+		for _, seg := range segments {
+			if seg == "" {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			return nil, nil, fmt.Errorf("env: invalid variable name '%s'", name)
+		}
+		entries = append(entries, envEntry{segments: segments, name: name, raw: raw})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	base := opts.Base
+	if base == nil {
+		base = eorm.NewRecord()
+	}
+	var overrides []Override
+	result, err := overlay(base, entries, "", &overrides)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Path < overrides[j].Path })
+	return result, overrides, nil
+}
+
+// overlay 自底向上构建新的 Record
+func overlay(base *eorm.Record, entries []envEntry, path string, overrides *[]Override) (*eorm.Record, error) {
+	// 按第一段路径分组，键名匹配基础配置中已有的键
+	groups := make(map[string][]envEntry)
+	var newKeys []string
+	for _, e := range entries {
+		key := e.segments[0]
+		for _, k := range base.Keys() {
+			if strings.EqualFold(k, key) {
+				key = k
+				break
+			}
+		}
+		if _, ok := groups[key]; !ok && !base.Has(key) {
+			newKeys = append(newKeys, key)
+		}
+		e.segments = e.segments[1:]
+		groups[key] = append(groups[key], e)
+	}
+
+	result := eorm.NewRecord()
+	for _, key := range append(base.Keys(), newKeys...) {
+		group, touched := groups[key]
+		if !touched {
+			result.Set(key, cloneValue(base.Get(key)))
+			continue
+		}
+
+		childPath := joinPath(path, key)
+		var nested []envEntry
+		var leaf *envEntry
+		for i := range group {
+			if len(group[i].segments) == 0 {
+				leaf = &group[i]
+			} else {
+				nested = append(nested, group[i])
+			}
+		}
+
+		old := base.Get(key)
+		var val interface{} = cloneValue(old)
+		if leaf != nil {
+			converted, err := convertLike(old, leaf.raw)
+			if err != nil {
+				return nil, fmt.Errorf("env: %s (%s): %v", leaf.name, childPath, err)
+			}
+			*overrides = append(*overrides, Override{Path: childPath, EnvVar: leaf.name, Old: old, New: converted})
+			val = converted
+		}
+
+		if len(nested) > 0 {
+			child := eorm.NewRecord()
+			if rec := asRecord(val); rec != nil {
+				child = rec
+			} else if val != nil {
+				return nil, fmt.Errorf("env: %s: '%s' is not a nested object", nested[0].name, childPath)
+			}
+			built, err := overlay(child, nested, childPath, overrides)
+			if err != nil {
+				return nil, err
+			}
+			val = built
+		}
+		result.Set(key, val)
+	}
+	return result, nil
+}
+
+// convertLike 按基础配置中已有值的类型转换环境变量字符串
+func convertLike(old interface{}, raw string) (interface{}, error) {
+	if old == nil {
+		return raw, nil
+	}
+	if rec := asRecord(old); rec != nil {
+		// 嵌套对象可以整体用 JSON 覆盖
+		if !json.Valid([]byte(raw)) {
+			return nil, fmt.Errorf("expected a JSON object")
+		}
+		parsed := eorm.NewRecord().FromJson(raw)
+		if parsed.IsEmpty() && strings.TrimSpace(raw) != "{}" {
+			return nil, fmt.Errorf("expected a JSON object")
+		}
+		return parsed, nil
+	}
+
+	switch old.(type) {
+	case string:
+		return raw, nil
+	case bool:
+		return eorm.Convert.ToBoolWithError(raw)
+	case int:
+		return eorm.Convert.ToIntWithError(raw)
+	case int8:
+		return eorm.Convert.ToInt8WithError(raw)
+	case int16:
+		return eorm.Convert.ToInt16WithError(raw)
+	case int32:
+		return eorm.Convert.ToInt32WithError(raw)
+	case int64:
+		return eorm.Convert.ToInt64WithError(raw)
+	case uint:
+		return eorm.Convert.ToUintWithError(raw)
+	case uint8:
+		return eorm.Convert.ToUint8WithError(raw)
+	case uint16:
+		return eorm.Convert.ToUint16WithError(raw)
+	case uint32:
+		return eorm.Convert.ToUint32WithError(raw)
+	case uint64:
+		return eorm.Convert.ToUint64WithError(raw)
+	case float32:
+		return eorm.Convert.ToFloat32WithError(raw)
+	case float64:
+		return eorm.Convert.ToFloat64WithError(raw)
+	case time.Duration:
+		return eorm.Convert.ToDurationWithError(raw)
+	case time.Time:
+		return eorm.Convert.ToTimeWithError(raw)
+	case []string:
+		return splitList(raw), nil
+	}
+
+	if kind := reflect.TypeOf(old).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		// JSON 数组原样解析，否则按逗号分隔
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			var items []interface{}
+			if err := json.Unmarshal([]byte(raw), &items); err != nil {
+				return nil, fmt.Errorf("invalid JSON array: %v", err)
+			}
+			return items, nil
+		}
+		parts := splitList(raw)
+		items := make([]interface{}, len(parts))
+		for i, p := range parts {
+			items[i] = p
+		}
+		return items, nil
+	}
+	return raw, nil
+}
+
+func splitList(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return []string{}
+	}
+	parts := strings.Split(raw, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// asRecord 将 Record 或 *Record 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch rec := v.(type) {
+	case *eorm.Record:
+		return rec
+	case nil:
+		return nil
+	}
+	if reflect.TypeOf(v) == recordType {
+		holder, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return holder
+	}
+	return nil
+}
+
+// cloneValue 深拷贝值，保证返回的 Record 与基础配置互不影响
+func cloneValue(v interface{}) interface{} {
+	if rec := asRecord(v); rec != nil {
+		return rec.DeepClone()
+	}
+	if v == nil {
+		return nil
+	}
+	holder := eorm.NewRecord().Set("v", v).DeepClone()
+	return holder.Get("v")
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例16：环境变量覆盖配置
+// 演示 FromEnv 将 APP_DATABASE__HOST 形式的环境变量按路径覆盖到基础配置上
+func main() {
+	fmt.Println("========== 环境变量覆盖配置示例 ==========")
+
+	// 1. 基础配置（与使用指南中的多环境配置一致）
+	baseConfig := eorm.NewRecord().FromJson(`{
+		"database": {
+			"host": "localhost",
+			"port": 3306
+		},
+		"cache": {
+			"enabled": true,
+			"ttl": 3600
+		}
+	}`)
+	baseConfig.Set("timeout", 30*time.Second)
+	baseConfig.Set("allowed_origins", []string{"http://localhost"})
+	fmt.Printf("1. 基础配置: %v\n", baseConfig.ToJson())
+
+	// 2. 使用指定的环境变量列表覆盖
+	fmt.Println("\n2. 使用环境变量覆盖")
+	environ := []string{
+		"APP_DATABASE__HOST=db.prod.internal",
+		"APP_DATABASE__PORT=3307",
+		"APP_DATABASE__MAX_CONNS=50",
+		"APP_CACHE__ENABLED=false",
+		"APP_TIMEOUT=1m30s",
+		"APP_ALLOWED_ORIGINS=https://a.example.com, https://b.example.com",
+		"OTHER_DATABASE__HOST=ignored",
+	}
+	prodConfig, overrides, err := FromEnv("APP", EnvOptions{Base: baseConfig, Environ: environ})
+	if err != nil {
+		fmt.Printf("   覆盖失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   生产配置: %v\n", prodConfig.ToJson())
+
+	// 3. 类型按基础配置转换
+	fmt.Println("\n3. 类型按基础配置转换")
+	database, _ := prodConfig.GetRecord("database")
+	cache, _ := prodConfig.GetRecord("cache")
+	fmt.Printf("   database.port: %v (%T)\n", database.Get("port"), database.Get("port"))
+	fmt.Printf("   database.max_conns: %v (%T，基础配置中不存在，保持字符串)\n", database.Get("max_conns"), database.Get("max_conns"))
+	fmt.Printf("   cache.enabled: %v (%T)\n", cache.Get("enabled"), cache.Get("enabled"))
+	fmt.Printf("   timeout: %v (%T)\n", prodConfig.Get("timeout"), prodConfig.Get("timeout"))
+	origins, _ := prodConfig.GetStringSlice("allowed_origins")
+	fmt.Printf("   allowed_origins: %v\n", origins)
+
+	// 4. 查看覆盖了哪些键
+	fmt.Println("\n4. 覆盖列表")
+	for _, o := range overrides {
+		fmt.Printf("   %-20s <- %-28s %v => %v\n", o.Path, o.EnvVar, o.Old, o.New)
+	}
+
+	// 5. 基础配置不受影响
+	fmt.Println("\n5. 基础配置不受影响")
+	host, _ := baseConfig.GetStringByPath("database.host")
+	fmt.Printf("   基础配置 database.host: %s\n", host)
+
+	// 6. 读取真实的进程环境变量
+	fmt.Println("\n6. 读取进程环境变量")
+	os.Setenv("DEMO_CACHE__TTL", "600")
+	defer os.Unsetenv("DEMO_CACHE__TTL")
+	devConfig, overrides, err := FromEnv("DEMO", EnvOptions{Base: baseConfig})
+	if err != nil {
+		fmt.Printf("   覆盖失败: %v\n", err)
+	} else {
+		ttl, _ := devConfig.GetStringByPath("cache.ttl")
+		fmt.Printf("   cache.ttl: %s，覆盖了 %d 个键\n", ttl, len(overrides))
+	}
+
+	// 7. 自定义分隔符
+	fmt.Println("\n7. 自定义分隔符")
+	dotConfig, _, err := FromEnv("APP", EnvOptions{
+		Base:      baseConfig,
+		Separator: "_",
+		Environ:   []string{"APP_DATABASE_HOST=127.0.0.1"},
+	})
+	if err != nil {
+		fmt.Printf("   覆盖失败: %v\n", err)
+	} else {
+		host, _ := dotConfig.GetStringByPath("database.host")
+		fmt.Printf("   database.host: %s\n", host)
+	}
+
+	// 8. 错误处理
+	fmt.Println("\n8. 错误处理")
+	if _, _, err := FromEnv("APP", EnvOptions{Base: baseConfig, Environ: []string{"APP_DATABASE__PORT=abc"}}); err != nil {
+		fmt.Printf("   类型转换失败 (预期): %v\n", err)
+	}
+	if _, _, err := FromEnv("APP", EnvOptions{Base: baseConfig, Environ: []string{"APP_TIMEOUT__UNIT=s"}}); err != nil {
+		fmt.Printf("   路径冲突 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// ResolverFunc 解析 ${name:arg} 形式的占位符，arg 为冒号后的内容
+type ResolverFunc func(arg string) (string, error)
+
+// InterpolateOptions 控制 Interpolate 的行为
+type InterpolateOptions struct {
+	// LookupEnv 解析 ${ENV:NAME}，为 nil 时使用 os.LookupEnv
+	LookupEnv func(name string) (string, bool)
+	// Resolvers 自定义命名空间解析器，如 Resolvers["SECRET"] 解析 ${SECRET:db/password}
+	Resolvers map[string]ResolverFunc
+	// AllowMissing 为 true 时，无法解析的占位符原样保留；默认返回错误
+	AllowMissing bool
+}
+
+// Interpolate 解析 Record 中所有字符串值里的占位符，返回新的 Record，原 Record 不会被修改
+// 支持的写法：
+// 1. ${database.host}：引用同一 Record 中的其他路径，数组元素使用下标，如 ${servers.0.host}
+// 2. ${ENV:HOME}：引用环境变量
+// 3. ${NAME:arg}：调用 Resolvers 中注册的自定义解析器
+// 4. $${：转义，输出字面量 ${
+// 整个字符串只有一个路径引用时保留被引用值的类型，如 "${database.port}" 解析为数值而不是字符串
+// 引用形成循环时返回错误，错误信息包含完整的引用链
+func Interpolate(r *eorm.Record, opts InterpolateOptions) (*eorm.Record, error) {
+	if r == nil {
+		return nil, fmt.Errorf("interpolate: record is nil")
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	in := &interpolator{
+		root:     r,
+		opts:     opts,
+		resolved: make(map[string]interface{}),
+		active:   make(map[string]bool),
+	}
+	return in.record(r, "")
+}
+
+type interpolator struct {
+	root     *eorm.Record
+	opts     InterpolateOptions
+	resolved map[string]interface{} // 已解析路径的缓存
+	active   map[string]bool        // 正在解析的路径，用于检测循环
+	stack    []string               // 解析链，用于错误信息
+}
+
+func (in *interpolator) record(r *eorm.Record, path string) (*eorm.Record, error) {
+	result := eorm.NewRecord()
+	for _, key := range r.Keys() {
+		val, err := in.value(fieldOf(r, key), joinPath(path, key))
+		if err != nil {
+			return nil, err
+		}
+		result.Set(key, val)
+	}
+	return result, nil
+}
+
+func (in *interpolator) value(v interface{}, path string) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return in.resolvePath(path, val)
+	case *eorm.Record:
+		return in.record(val, path)
+	case []*eorm.Record:
+		records := make([]*eorm.Record, len(val))
+		for i, item := range val {
+			rec, err := in.record(item, joinPath(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			records[i] = rec
+		}
+		return records, nil
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			resolved, err := in.value(item, joinPath(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = resolved
+		}
+		return items, nil
+	case []string:
+		items := make([]string, len(val))
+		for i, item := range val {
+			resolved, err := in.resolvePath(joinPath(path, strconv.Itoa(i)), item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = eorm.Convert.ToString(resolved)
+		}
+		return items, nil
+	}
+	return v, nil
+}
+
+// resolvePath 解析 path 处的字符串 raw，结果按路径缓存
+func (in *interpolator) resolvePath(path, raw string) (interface{}, error) {
+	// Record 的键名大小写不敏感，缓存和循环检测统一使用小写路径
+	key := strings.ToLower(path)
+	if v, ok := in.resolved[key]; ok {
+		return v, nil
+	}
+	if in.active[key] {
+		start := 0
+		for i, p := range in.stack {
+			if strings.EqualFold(p, path) {
+				start = i
+				break
+			}
+		}
+		chain := append(in.stack[start:], path)
+		return nil, fmt.Errorf("interpolate: cycle detected: %s", strings.Join(chain, " -> "))
+	}
+	in.active[key] = true
+	in.stack = append(in.stack, path)
+	defer func() {
+		delete(in.active, key)
+		in.stack = in.stack[:len(in.stack)-1]
+	}()
+
+	v, err := in.resolveString(raw, path)
+	if err != nil {
+		return nil, err
+	}
+	in.resolved[key] = v
+	return v, nil
+}
+
+// resolveString 扫描字符串中的占位符
+func (in *interpolator) resolveString(s, path string) (interface{}, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	// 整个字符串只有一个占位符时保留被引用值的类型
+	if strings.HasPrefix(s, "${") && strings.Index(s, "}") == len(s)-1 && strings.Count(s, "${") == 1 {
+		return in.resolveExpr(s[2:len(s)-1], path, s)
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			buf.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			buf.WriteByte(s[i])
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("interpolate: '%s': unclosed placeholder in %q", path, s)
+		}
+		placeholder := s[i : i+end+1]
+		v, err := in.resolveExpr(s[i+2:i+end], path, placeholder)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(eorm.Convert.ToString(v))
+		i += end + 1
+	}
+	return buf.String(), nil
+}
+
+// resolveExpr 解析单个占位符的内容，placeholder 为原始文本，用于 AllowMissing 时原样保留
+func (in *interpolator) resolveExpr(expr, path, placeholder string) (interface{}, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("interpolate: '%s': empty placeholder", path)
+	}
+
+	if ns, arg, ok := strings.Cut(expr, ":"); ok {
+		if ns == "ENV" {
+			if v, found := in.opts.LookupEnv(arg); found {
+				return v, nil
+			}
+			return in.missing(path, placeholder, fmt.Sprintf("environment variable '%s' is not set", arg))
+		}
+		resolver, found := in.opts.Resolvers[ns]
+		if !found {
+			return in.missing(path, placeholder, fmt.Sprintf("unknown resolver '%s'", ns))
+		}
+		v, err := resolver(arg)
+		if err != nil {
+			return nil, fmt.Errorf("interpolate: '%s': resolver '%s': %v", path, ns, err)
+		}
+		return v, nil
+	}
+
+	raw, found := lookupPath(in.root, expr)
+	if !found {
+		return in.missing(path, placeholder, fmt.Sprintf("path '%s' not found", expr))
+	}
+	if str, ok := raw.(string); ok {
+		return in.resolvePath(expr, str)
+	}
+	if isRecordValue(raw) {
+		// 引用整个对象时输出 JSON，与 GetStringByPath 的行为一致
+		rec, err := in.record(asRecord(raw), expr)
+		if err != nil {
+			return nil, err
+		}
+		return rec.ToJson(), nil
+	}
+	return raw, nil
+}
+
+func (in *interpolator) missing(path, placeholder, reason string) (interface{}, error) {
+	if in.opts.AllowMissing {
+		return placeholder, nil
+	}
+	return nil, fmt.Errorf("interpolate: '%s': %s", path, reason)
+}
+
+// lookupPath 按点分路径读取原始值，支持数组下标
+func lookupPath(r *eorm.Record, path string) (interface{}, bool) {
+	var cur interface{} = r
+	for _, seg := range strings.Split(path, ".") {
+		if rec := asRecord(cur); rec != nil {
+			if !rec.Has(seg) {
+				return nil, false
+			}
+			cur = fieldOf(rec, seg)
+			continue
+		}
+		idx, err := strconv.Atoi(seg)
+		if err != nil {
+			return nil, false
+		}
+		rv := reflect.ValueOf(cur)
+		if cur == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || idx < 0 || idx >= rv.Len() {
+			return nil, false
+		}
+		cur = rv.Index(idx).Interface()
+	}
+	return cur, true
+}
+
+// fieldOf 读取字段值，Record 值类型统一转为 *Record
+func fieldOf(r *eorm.Record, key string) interface{} {
+	v := r.Get(key)
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := r.GetRecord(key)
+		return rec
+	}
+	return v
+}
+
+func isRecordValue(v interface{}) bool {
+	return asRecord(v) != nil
+}
+
+func asRecord(v interface{}) *eorm.Record {
+	if rec, ok := v.(*eorm.Record); ok {
+		return rec
+	}
+	if v != nil && reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例17：配置占位符与交叉引用
+// 演示 Interpolate 解析 ${path}、${ENV:NAME}、自定义解析器、转义以及循环检测
+func main() {
+	fmt.Println("========== Record 占位符解析示例 ==========")
+
+	env := map[string]string{"HOME": "/home/app", "DB_PASSWORD": "s3cret"}
+	lookupEnv := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	// 1. 引用同一 Record 中的其他路径
+	fmt.Println("\n1. 引用同一 Record 中的其他路径")
+	config := eorm.NewRecord().FromJson(`{
+		"database": {
+			"host": "db.internal",
+			"port": 3306,
+			"name": "shop",
+			"addr": "${database.host}:${database.port}",
+			"dsn": "root:${ENV:DB_PASSWORD}@tcp(${database.addr})/${database.name}"
+		},
+		"cache": {
+			"port": "${database.port}",
+			"dir": "${ENV:HOME}/cache"
+		},
+		"servers": [
+			{"host": "10.0.0.1"},
+			{"host": "10.0.0.2"}
+		],
+		"primary": "${servers.0.host}",
+		"template": "Hello $${name}!"
+	}`)
+	resolved, err := Interpolate(config, InterpolateOptions{LookupEnv: lookupEnv})
+	if err != nil {
+		fmt.Printf("   解析失败: %v\n", err)
+		return
+	}
+	addr, _ := resolved.GetStringByPath("database.addr")
+	dsn, _ := resolved.GetStringByPath("database.dsn")
+	cacheDir, _ := resolved.GetStringByPath("cache.dir")
+	cache, _ := resolved.GetRecord("cache")
+	fmt.Printf("   database.addr: %s\n", addr)
+	fmt.Printf("   database.dsn: %s\n", dsn)
+	fmt.Printf("   cache.dir: %s\n", cacheDir)
+	fmt.Printf("   cache.port: %v (%T，单个占位符保留原类型)\n", cache.Get("port"), cache.Get("port"))
+	fmt.Printf("   primary: %s\n", resolved.GetString("primary"))
+	fmt.Printf("   template: %s (转义后的字面量)\n", resolved.GetString("template"))
+
+	// 2. 原 Record 不受影响
+	fmt.Println("\n2. 原 Record 不受影响")
+	original, _ := config.GetStringByPath("database.addr")
+	fmt.Printf("   原 database.addr: %s\n", original)
+
+	// 3. 自定义解析器
+	fmt.Println("\n3. 自定义解析器")
+	secrets := map[string]string{"db/password": "vault-pass"}
+	withSecret := eorm.NewRecord().Set("password", "${SECRET:db/password}").Set("upper", "${UPPER:abc}")
+	resolved3, err := Interpolate(withSecret, InterpolateOptions{
+		Resolvers: map[string]ResolverFunc{
+			"SECRET": func(arg string) (string, error) {
+				if v, ok := secrets[arg]; ok {
+					return v, nil
+				}
+				return "", fmt.Errorf("secret '%s' not found", arg)
+			},
+			"UPPER": func(arg string) (string, error) {
+				return strings.ToUpper(arg), nil
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("   解析失败: %v\n", err)
+	} else {
+		fmt.Printf("   Record: %v\n", resolved3.ToJson())
+	}
+
+	// 4. 循环引用检测
+	fmt.Println("\n4. 循环引用检测")
+	cyclic := eorm.NewRecord().FromJson(`{
+		"a": {"value": "${b.value}"},
+		"b": {"value": "prefix-${c}"},
+		"c": "${a.value}"
+	}`)
+	if _, err := Interpolate(cyclic, InterpolateOptions{}); err != nil {
+		fmt.Printf("   循环引用 (预期): %v\n", err)
+	}
+
+	// 5. 缺失的引用
+	fmt.Println("\n5. 缺失的引用")
+	missing := eorm.NewRecord().Set("url", "http://${host}:${ENV:NOT_SET_PORT}/api")
+	if _, err := Interpolate(missing, InterpolateOptions{LookupEnv: lookupEnv}); err != nil {
+		fmt.Printf("   默认返回错误 (预期): %v\n", err)
+	}
+	lenient, err := Interpolate(missing, InterpolateOptions{LookupEnv: lookupEnv, AllowMissing: true})
+	if err == nil {
+		fmt.Printf("   AllowMissing 原样保留: %s\n", lenient.GetString("url"))
+	}
+
+	// 6. 引用整个对象
+	fmt.Println("\n6. 引用整个对象")
+	objRef := eorm.NewRecord().FromJson(`{
+		"profile": {"city": "北京", "zip": "100000"},
+		"summary": "profile=${profile}"
+	}`)
+	if resolved6, err := Interpolate(objRef, InterpolateOptions{}); err == nil {
+		fmt.Printf("   summary: %s\n", resolved6.GetString("summary"))
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 示例18：配置文件热加载
+// 演示 ConfigWatcher 轮询配置文件、按路径通知订阅者以及读取一致的配置快照
+func main() {
+	fmt.Println("========== 配置文件热加载示例 ==========")
+
+	dir, err := os.MkdirTemp("", "config-watcher")
+	if err != nil {
+		fmt.Printf("创建临时目录失败: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	configPath := filepath.Join(dir, "config.json")
+
+	writeConfig(configPath, `{
+		"database": {"host": "localhost", "port": 3306},
+		"cache": {"enabled": true, "ttl": 3600}
+	}`)
+
+	// 1. 创建 ConfigWatcher，首次加载配置
+	fmt.Println("\n1. 创建 ConfigWatcher")
+	watcher, err := NewConfigWatcher(configPath, WatcherOptions{
+		Interval: 20 * time.Millisecond,
+		OnError: func(err error) {
+			fmt.Printf("   [OnError] %v\n", err)
+		},
+	})
+	if err != nil {
+		fmt.Printf("   创建失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   当前配置: %v\n", watcher.Snapshot().ToJson())
+
+	// 2. 订阅路径变化
+	fmt.Println("\n2. 订阅路径变化")
+	var mu sync.Mutex
+	var logs []string
+	record := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}
+	reloaded := make(chan struct{}, 10)
+
+	watcher.OnChange("cache.ttl", func(c ConfigChange) {
+		record("[cache.ttl] %v -> %v", c.Old, c.New)
+	})
+	unsubscribe := watcher.OnChange("database", func(c ConfigChange) {
+		record("[database] %s %s: %v -> %v", c.Path, c.Kind, c.Old, c.New)
+	})
+	watcher.OnChange("*", func(c ConfigChange) {
+		record("[*] %s %s", c.Path, c.Kind)
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+	fmt.Println("   已订阅 cache.ttl、database 和 *")
+
+	// 3. 启动轮询并修改配置文件
+	fmt.Println("\n3. 修改配置文件，等待热加载")
+	watcher.Start()
+
+	// 读取方持有旧快照，热加载期间不受影响
+	before := watcher.Snapshot()
+
+	writeConfig(configPath, `{
+		"database": {"host": "db.internal", "port": 3306, "pool": 20},
+		"cache": {"enabled": true, "ttl": 600}
+	}`)
+	waitReload(reloaded)
+	printLogs(&mu, &logs)
+
+	// 4. 快照一致性
+	fmt.Println("\n4. 快照一致性")
+	oldTTL, _ := before.GetStringByPath("cache.ttl")
+	newTTL, _ := watcher.Snapshot().GetStringByPath("cache.ttl")
+	fmt.Printf("   旧快照 cache.ttl: %s，新快照 cache.ttl: %s\n", oldTTL, newTTL)
+
+	// 5. 取消订阅
+	fmt.Println("\n5. 取消 database 订阅后再次修改")
+	unsubscribe()
+	writeConfig(configPath, `{
+		"database": {"host": "db.internal", "port": 3307},
+		"cache": {"enabled": false, "ttl": 600}
+	}`)
+	waitReload(reloaded)
+	printLogs(&mu, &logs)
+
+	// 以下步骤停止轮询，改为手动调用 Reload
+	watcher.Stop()
+
+	// 6. 配置文件格式错误时保留旧配置
+	fmt.Println("\n6. 配置文件格式错误")
+	writeConfig(configPath, `{"database": `)
+	if _, err := watcher.Reload(); err != nil {
+		fmt.Printf("   重新加载失败 (预期): %v\n", err)
+	}
+	port, _ := watcher.Snapshot().GetStringByPath("database.port")
+	fmt.Printf("   仍在使用旧配置 database.port: %s\n", port)
+
+	// 7. 手动调用 Reload 并获取变化列表
+	fmt.Println("\n7. 手动调用 Reload 并获取变化列表")
+	writeConfig(configPath, `{"database": {"host": "db.internal", "port": 3307}}`)
+	changes, err := watcher.Reload()
+	if err != nil {
+		fmt.Printf("   重新加载失败: %v\n", err)
+	}
+	for _, c := range changes {
+		fmt.Printf("   %s %s\n", c.Path, c.Kind)
+	}
+
+	// 8. 回调中调用 Reload，Path 保留键名的原始写法
+	fmt.Println("\n8. 回调中调用 Reload")
+	stopNested := watcher.OnChange("database.maxConns", func(c ConfigChange) {
+		_, err := watcher.Reload()
+		fmt.Printf("   %s %s: %v -> %v，回调中 Reload 返回 %v\n", c.Path, c.Kind, c.Old, c.New, err)
+	})
+	writeConfig(configPath, `{"database": {"host": "db.internal", "port": 3307, "maxConns": 10}}`)
+	if _, err := watcher.Reload(); err != nil {
+		fmt.Printf("   重新加载失败: %v\n", err)
+	}
+	stopNested()
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+func writeConfig(path, content string) {
+	// 先写临时文件再重命名，避免轮询读到写了一半的文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		fmt.Printf("写入配置失败: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		fmt.Printf("替换配置失败: %v\n", err)
+	}
+}
+
+func waitReload(reloaded chan struct{}) {
+	select {
+	case <-reloaded:
+		// 同一次热加载会连续触发多个回调，稍等让回调执行完
+		time.Sleep(50 * time.Millisecond)
+		drain(reloaded)
+	case <-time.After(2 * time.Second):
+		fmt.Println("   ❌ 等待热加载超时")
+	}
+}
+
+func drain(ch chan struct{}) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
+
+func printLogs(mu *sync.Mutex, logs *[]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, l := range *logs {
+		fmt.Printf("   %s\n", l)
+	}
+	*logs = nil
+}
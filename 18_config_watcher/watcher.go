@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// ChangeKind 表示配置项的变化类型
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Modified
+	Removed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	}
+	return "modified"
+}
+
+// ConfigChange 描述一个叶子路径的变化
+type ConfigChange struct {
+	Path     string
+	Kind     ChangeKind
+	Old      interface{}
+	New      interface{}
+	Snapshot *eorm.Record // 变化后的完整配置快照
+}
+
+// WatcherOptions 控制 ConfigWatcher 的行为
+type WatcherOptions struct {
+	// Interval 轮询间隔，默认 1 秒
+	Interval time.Duration
+	// Loader 将文件内容解析为 Record，默认按 JSON 解析
+	Loader func(data []byte) (*eorm.Record, error)
+	// OnError 后台重新加载失败时调用，失败时继续使用旧配置
+	OnError func(err error)
+}
+
+type subscription struct {
+	pattern string
+	fn      func(ConfigChange)
+}
+
+// ConfigWatcher 监听配置文件，文件内容变化时重新构建 Record，并按路径通知订阅者
+// 通过轮询文件内容的哈希检测变化，不依赖平台相关的文件系统通知
+// Snapshot 返回的 Record 在替换后不会再被修改，读取方可以在整个处理过程中持有同一份快照
+type ConfigWatcher struct {
+	path    string
+	opts    WatcherOptions
+	current atomic.Pointer[eorm.Record]
+
+	reloadMu sync.Mutex // 串行化重新加载
+	lastHash [sha256.Size]byte
+
+	// 每次重新加载的变化在 reloadMu 内按快照顺序加入 pending，
+	// 由一个 goroutine 在不持有锁的情况下依次通知，保证订阅者看到的顺序与快照一致
+	notifyMu  sync.Mutex
+	pending   [][]ConfigChange
+	notifying bool
+
+	subMu  sync.RWMutex
+	subs   map[uint64]subscription
+	nextID uint64
+
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewConfigWatcher 加载配置文件并创建 ConfigWatcher，首次加载失败时返回错误
+// 创建后需要调用 Start 开始后台轮询，或手动调用 Reload
+func NewConfigWatcher(path string, opts WatcherOptions) (*ConfigWatcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Loader == nil {
+		opts.Loader = loadJSON
+	}
+	w := &ConfigWatcher{
+		path: path,
+		opts: opts,
+		subs: make(map[uint64]subscription),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func loadJSON(data []byte) (*eorm.Record, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON")
+	}
+	return eorm.NewRecord().FromJson(string(data)), nil
+}
+
+// Snapshot 返回当前配置快照，调用方不应修改它；需要修改时先 DeepClone
+func (w *ConfigWatcher) Snapshot() *eorm.Record {
+	return w.current.Load()
+}
+
+// OnChange 订阅路径变化，返回取消订阅的函数
+// pattern 为 "cache.ttl" 时只接收该路径的变化；为 "cache" 时接收 cache 下所有路径的变化；为 "*" 或空字符串时接收全部变化
+func (w *ConfigWatcher) OnChange(pattern string, fn func(ConfigChange)) (unsubscribe func()) {
+	w.subMu.Lock()
+	defer w.subMu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = subscription{pattern: strings.ToLower(pattern), fn: fn}
+	return func() {
+		w.subMu.Lock()
+		defer w.subMu.Unlock()
+		delete(w.subs, id)
+	}
+}
+
+// Start 开始后台轮询，重复调用无效
+func (w *ConfigWatcher) Start() {
+	if !w.started.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if _, err := w.Reload(); err != nil && w.opts.OnError != nil {
+					w.opts.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止后台轮询并等待轮询协程退出
+func (w *ConfigWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	if w.started.Load() {
+		<-w.done
+	}
+}
+
+// Reload 立即检查文件，内容变化时重新加载并通知订阅者，返回本次的变化列表
+// 解析失败时保留旧配置并返回错误；通知在释放锁之后进行，回调中可以再次调用 Reload
+// 其他 goroutine 正在通知时，本次的变化排在其后由该 goroutine 通知，Reload 可能在通知完成前返回
+func (w *ConfigWatcher) Reload() ([]ConfigChange, error) {
+	changes, err := w.reload()
+	if err != nil {
+		return nil, err
+	}
+	w.dispatch()
+	return changes, nil
+}
+
+// dispatch 按加入顺序通知 pending 中的变化，同一时间只有一个 goroutine 通知
+func (w *ConfigWatcher) dispatch() {
+	w.notifyMu.Lock()
+	if w.notifying {
+		w.notifyMu.Unlock()
+		return
+	}
+	w.notifying = true
+	for len(w.pending) > 0 {
+		changes := w.pending[0]
+		w.pending = w.pending[1:]
+		w.notifyMu.Unlock()
+		w.notify(changes)
+		w.notifyMu.Lock()
+	}
+	w.notifying = false
+	w.notifyMu.Unlock()
+}
+
+// reload 在 reloadMu 保护下读取文件并替换快照，需要通知的变化加入 pending
+func (w *ConfigWatcher) reload() ([]ConfigChange, error) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("config watcher: read %s: %v", w.path, err)
+	}
+	hash := sha256.Sum256(data)
+	old := w.current.Load()
+	if old != nil && hash == w.lastHash {
+		return nil, nil
+	}
+
+	next, err := w.opts.Loader(data)
+	if err != nil {
+		return nil, fmt.Errorf("config watcher: load %s: %v", w.path, err)
+	}
+	w.lastHash = hash
+	w.current.Store(next)
+	if old == nil {
+		return nil, nil
+	}
+
+	changes := DiffRecords(old, next)
+	for i := range changes {
+		changes[i].Snapshot = next
+	}
+	if len(changes) > 0 {
+		w.notifyMu.Lock()
+		w.pending = append(w.pending, changes)
+		w.notifyMu.Unlock()
+	}
+	return changes, nil
+}
+
+func (w *ConfigWatcher) notify(changes []ConfigChange) {
+	w.subMu.RLock()
+	ids := make([]uint64, 0, len(w.subs))
+	for id := range w.subs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	subs := make([]subscription, len(ids))
+	for i, id := range ids {
+		subs[i] = w.subs[id]
+	}
+	w.subMu.RUnlock()
+
+	// 在锁外回调，订阅者可以在回调中取消订阅或读取快照
+	for _, change := range changes {
+		for _, sub := range subs {
+			if matchPattern(sub.pattern, change.Path) {
+				sub.fn(change)
+			}
+		}
+	}
+}
+
+func matchPattern(pattern, path string) bool {
+	path = strings.ToLower(path)
+	return pattern == "" || pattern == "*" || pattern == path || strings.HasPrefix(path, pattern+".")
+}
+
+// DiffRecords 比较两个 Record，按路径排序返回所有叶子路径的变化
+// 嵌套 Record 递归比较，数组等其他值按 JSON 内容整体比较；路径不区分大小写，Path 保留 Record 中键名的原始写法
+func DiffRecords(old, next *eorm.Record) []ConfigChange {
+	oldLeaves := make(map[string]leaf)
+	newLeaves := make(map[string]leaf)
+	flatten(old, "", oldLeaves)
+	flatten(next, "", newLeaves)
+
+	var changes []ConfigChange
+	for key, ol := range oldLeaves {
+		nl, ok := newLeaves[key]
+		if !ok {
+			changes = append(changes, ConfigChange{Path: ol.path, Kind: Removed, Old: ol.value})
+		} else if !sameValue(ol.value, nl.value) {
+			changes = append(changes, ConfigChange{Path: nl.path, Kind: Modified, Old: ol.value, New: nl.value})
+		}
+	}
+	for key, nl := range newLeaves {
+		if _, ok := oldLeaves[key]; !ok {
+			changes = append(changes, ConfigChange{Path: nl.path, Kind: Added, New: nl.value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// leaf 是 flatten 得到的一个叶子，map 的键为小写路径，path 保留原始写法
+type leaf struct {
+	path  string
+	value interface{}
+}
+
+func flatten(r *eorm.Record, prefix string, out map[string]leaf) {
+	if r == nil {
+		return
+	}
+	for _, key := range r.Keys() {
+		path := joinPath(prefix, key)
+		val := r.Get(key)
+		if _, ok := val.(*eorm.Record); ok || reflect.TypeOf(val) == recordType {
+			nested, _ := r.GetRecord(key)
+			if nested.IsEmpty() {
+				out[strings.ToLower(path)] = leaf{path, nested}
+				continue
+			}
+			flatten(nested, path, out)
+			continue
+		}
+		out[strings.ToLower(path)] = leaf{path, val}
+	}
+}
+
+func sameValue(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
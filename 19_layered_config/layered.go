@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+type layer struct {
+	name   string
+	record *eorm.Record
+}
+
+// PathSource 记录一个叶子路径的最终值来自哪一层
+type PathSource struct {
+	Path  string
+	Layer string
+	Value interface{}
+}
+
+// LayeredRecord 将多个命名的 Record 按层叠加，读取时从最上层开始查找
+// 典型的层次：defaults -> base file -> env file -> env vars -> cli flags
+// 各层的 Record 不会被修改，需要单独的 Record 时使用 Flatten
+type LayeredRecord struct {
+	mu     sync.RWMutex
+	layers []layer // 下标越大优先级越高
+}
+
+// NewLayeredRecord 创建空的 LayeredRecord
+func NewLayeredRecord() *LayeredRecord {
+	return &LayeredRecord{}
+}
+
+// AddLayer 在最上层添加一层，同名层已存在时原位替换
+// 支持链式调用：NewLayeredRecord().AddLayer("defaults", d).AddLayer("file", f)
+func (l *LayeredRecord) AddLayer(name string, r *eorm.Record) *LayeredRecord {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.layers {
+		if l.layers[i].name == name {
+			l.layers[i].record = r
+			return l
+		}
+	}
+	l.layers = append(l.layers, layer{name: name, record: r})
+	return l
+}
+
+// RemoveLayer 删除指定层，层不存在时返回 false
+func (l *LayeredRecord) RemoveLayer(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.layers {
+		if l.layers[i].name == name {
+			l.layers = append(l.layers[:i], l.layers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Layer 返回指定层的 Record
+func (l *LayeredRecord) Layer(name string) (*eorm.Record, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, ly := range l.layers {
+		if ly.name == name {
+			return ly.record, true
+		}
+	}
+	return nil, false
+}
+
+// Layers 按优先级从低到高返回层名
+func (l *LayeredRecord) Layers() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, len(l.layers))
+	for i, ly := range l.layers {
+		names[i] = ly.name
+	}
+	return names
+}
+
+// Get 按点分路径读取值，从最上层开始查找，结果与 Flatten 一致
+// 路径指向嵌套 Record 时，返回所有层中该子树合并后的 Record；
+// 某一层在路径的上级处是普通值时（例如 database 为字符串），更下层的 database.host 不再可见
+func (l *LayeredRecord) Get(path string) (interface{}, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var found []*eorm.Record
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		v, ok, shadowed := lookupPath(l.layers[i].record, path)
+		if shadowed {
+			// 上层的普通值覆盖了路径的上级，更下层的值都不可见
+			break
+		}
+		if !ok {
+			continue
+		}
+		rec := asRecord(v)
+		if rec == nil {
+			if len(found) > 0 {
+				// 上层是对象，下层是普通值：对象完全覆盖下层
+				break
+			}
+			return v, true
+		}
+		found = append(found, rec)
+	}
+	if len(found) == 0 {
+		return nil, false
+	}
+	merged := eorm.NewRecord()
+	for i := len(found) - 1; i >= 0; i-- {
+		merged = mergeRecords(merged, found[i])
+	}
+	return merged, true
+}
+
+// GetString 按路径读取字符串，路径不存在时返回空字符串
+func (l *LayeredRecord) GetString(path string) string {
+	v, _ := l.Get(path)
+	if rec := asRecord(v); rec != nil {
+		return rec.ToJson()
+	}
+	return eorm.Convert.ToString(v)
+}
+
+// GetInt 按路径读取 int，路径不存在或无法转换时返回 0
+func (l *LayeredRecord) GetInt(path string) int {
+	v, _ := l.Get(path)
+	return eorm.Convert.ToInt(v)
+}
+
+// GetBool 按路径读取 bool，路径不存在或无法转换时返回 false
+func (l *LayeredRecord) GetBool(path string) bool {
+	v, _ := l.Get(path)
+	return eorm.Convert.ToBool(v)
+}
+
+// Source 返回路径的值来自哪一层，即包含该路径的最上层
+// 路径指向嵌套 Record 时，各叶子可能来自不同层，使用 Sources 查看每个叶子的来源
+func (l *LayeredRecord) Source(path string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		_, ok, shadowed := lookupPath(l.layers[i].record, path)
+		if shadowed {
+			break
+		}
+		if ok {
+			return l.layers[i].name, true
+		}
+	}
+	return "", false
+}
+
+// Sources 返回合并后每个叶子路径的值及其来源层，按路径排序
+func (l *LayeredRecord) Sources() []PathSource {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	sources := make(map[string]PathSource)
+	for _, ly := range l.layers {
+		collectSources(ly.record, "", ly.name, sources)
+	}
+	result := make([]PathSource, 0, len(sources))
+	for _, s := range sources {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// collectSources 下层先写入，上层覆盖；上层的普通值会清除下层同一子树下的叶子
+func collectSources(r *eorm.Record, prefix, name string, out map[string]PathSource) {
+	for _, key := range r.Keys() {
+		path := joinPath(prefix, key)
+		lower := strings.ToLower(path)
+		if rec := asRecord(r.Get(key)); rec != nil {
+			if !rec.IsEmpty() {
+				delete(out, lower)
+				collectSources(rec, path, name, out)
+			} else if !hasDescendant(out, lower) {
+				// 空对象合并时不会覆盖下层的子路径
+				out[lower] = PathSource{Path: path, Layer: name, Value: rec}
+			}
+			continue
+		}
+		for p := range out {
+			if strings.HasPrefix(p, lower+".") {
+				delete(out, p)
+			}
+		}
+		out[lower] = PathSource{Path: path, Layer: name, Value: r.Get(key)}
+	}
+}
+
+func hasDescendant(out map[string]PathSource, prefix string) bool {
+	for p := range out {
+		if strings.HasPrefix(p, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Flatten 将所有层合并为一个新的 Record，可以直接序列化或传给其他接口
+// 嵌套 Record 递归合并，其他值（包括数组）由上层整体覆盖
+func (l *LayeredRecord) Flatten() *eorm.Record {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	result := eorm.NewRecord()
+	for _, ly := range l.layers {
+		result = mergeRecords(result, ly.record)
+	}
+	return result
+}
+
+// mergeRecords 返回 lower 与 upper 合并后的新 Record，upper 优先
+func mergeRecords(lower, upper *eorm.Record) *eorm.Record {
+	result := eorm.NewRecord()
+	for _, key := range lower.Keys() {
+		lv := lower.Get(key)
+		if !upper.Has(key) {
+			result.Set(key, cloneValue(lv))
+			continue
+		}
+		uv := upper.Get(key)
+		lr, ur := asRecord(lv), asRecord(uv)
+		if lr != nil && ur != nil {
+			result.Set(key, mergeRecords(lr, ur))
+		} else {
+			result.Set(key, cloneValue(uv))
+		}
+	}
+	for _, key := range upper.Keys() {
+		if !lower.Has(key) {
+			result.Set(key, cloneValue(upper.Get(key)))
+		}
+	}
+	return result
+}
+
+// FromPaths 由点分路径构建嵌套 Record，常用于把命令行参数、环境变量转换为一层配置
+// 例如 {"database.host": "127.0.0.1"} 转换为 {"database": {"host": "127.0.0.1"}}
+func FromPaths(values map[string]interface{}) (*eorm.Record, error) {
+	paths := make([]string, 0, len(values))
+	for p := range values {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	result := eorm.NewRecord()
+	for _, p := range paths {
+		if p == "" || strings.Contains(p, "..") || strings.HasPrefix(p, ".") || strings.HasSuffix(p, ".") {
+			return nil, fmt.Errorf("layered: invalid path '%s'", p)
+		}
+		segments := strings.Split(p, ".")
+		// 从叶子开始构建子树，再与已有结果合并
+		var node interface{} = values[p]
+		for i := len(segments) - 1; i > 0; i-- {
+			node = eorm.NewRecord().Set(segments[i], node)
+		}
+		if existing := result.Get(segments[0]); existing != nil {
+			er, nr := asRecord(existing), asRecord(node)
+			if er == nil || nr == nil {
+				return nil, fmt.Errorf("layered: path '%s' conflicts with an existing value", p)
+			}
+			node = mergeRecords(er, nr)
+		}
+		result.Set(segments[0], node)
+	}
+	return result, nil
+}
+
+// lookupPath 按点分路径查找值，键名大小写不敏感
+// 路径的某个上级在这一层中存在但不是 Record 时 shadowed 为 true：合并时这个值整体覆盖下层的同名子树
+func lookupPath(r *eorm.Record, path string) (value interface{}, ok, shadowed bool) {
+	var cur interface{} = r
+	for _, seg := range strings.Split(path, ".") {
+		rec := asRecord(cur)
+		if rec == nil {
+			return nil, false, true
+		}
+		if !rec.Has(seg) {
+			return nil, false, false
+		}
+		cur = rec.Get(seg)
+	}
+	return cur, true, false
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+// cloneValue 深拷贝值，保证合并结果与各层互不影响
+func cloneValue(v interface{}) interface{} {
+	if rec := asRecord(v); rec != nil {
+		return rec.DeepClone()
+	}
+	if v == nil {
+		return nil
+	}
+	return eorm.NewRecord().Set("v", v).DeepClone().Get("v")
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例19：分层配置与来源追踪
+// 演示 LayeredRecord 叠加 defaults、配置文件、环境配置、环境变量和命令行参数，并查询每个值来自哪一层
+func main() {
+	fmt.Println("========== 分层配置示例 ==========")
+
+	// 1. 准备各层配置
+	fmt.Println("\n1. 准备各层配置")
+	defaults := eorm.NewRecord().
+		Set("app_name", "shop").
+		Set("debug", false).
+		Set("database", eorm.NewRecord().
+			Set("host", "localhost").
+			Set("port", 3306).
+			Set("pool", 10)).
+		Set("cache", eorm.NewRecord().
+			Set("enabled", true).
+			Set("ttl", 3600))
+
+	baseFile := eorm.NewRecord().FromJson(`{
+		"database": {"host": "db.internal", "user": "app"},
+		"allowed_origins": ["https://shop.example.com"]
+	}`)
+
+	prodFile := eorm.NewRecord().FromJson(`{
+		"database": {"host": "db.prod.internal", "pool": 50},
+		"cache": {"ttl": 600}
+	}`)
+
+	// 环境变量层，实际项目中可以由 os.Environ() 转换而来
+	envVars, _ := FromPaths(map[string]interface{}{
+		"database.password": "s3cret",
+	})
+
+	// 命令行参数层：只包含用户显式传入的参数
+	fs := flag.NewFlagSet("shop", flag.ContinueOnError)
+	fs.String("database.port", "3306", "数据库端口")
+	fs.Bool("debug", false, "调试模式")
+	_ = fs.Parse([]string{"--database.port=3307", "--debug"})
+	cliFlags, err := fromFlags(fs)
+	if err != nil {
+		fmt.Printf("   解析命令行参数失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   cli flags 层: %v\n", cliFlags.ToJson())
+
+	// 2. 叠加各层
+	fmt.Println("\n2. 叠加各层（从低到高）")
+	config := NewLayeredRecord().
+		AddLayer("defaults", defaults).
+		AddLayer("base.json", baseFile).
+		AddLayer("prod.json", prodFile).
+		AddLayer("env", envVars).
+		AddLayer("flags", cliFlags)
+	fmt.Printf("   层: %v\n", config.Layers())
+
+	// 3. 自上而下读取
+	fmt.Println("\n3. 自上而下读取")
+	for _, path := range []string{"app_name", "debug", "database.host", "database.port", "database.pool", "database.password", "cache.ttl"} {
+		source, _ := config.Source(path)
+		fmt.Printf("   %-18s = %-18s (来自 %s)\n", path, config.GetString(path), source)
+	}
+	fmt.Printf("   database.port (int): %d\n", config.GetInt("database.port"))
+
+	// 4. 读取整个子树
+	fmt.Println("\n4. 读取整个子树（各层合并）")
+	database, _ := config.Get("database")
+	fmt.Printf("   database: %v\n", database)
+
+	// 5. 所有叶子的来源
+	fmt.Println("\n5. 所有叶子的来源")
+	for _, s := range config.Sources() {
+		fmt.Printf("   %-18s <- %s\n", s.Path, s.Layer)
+	}
+
+	// 6. Flatten 为普通 Record
+	fmt.Println("\n6. Flatten 为普通 Record")
+	flat := config.Flatten()
+	fmt.Printf("   %v\n", flat.ToJson())
+	host, _ := flat.GetStringByPath("database.host")
+	fmt.Printf("   flat database.host: %s\n", host)
+
+	// 7. 各层不受影响
+	fmt.Println("\n7. 各层不受影响")
+	flatDB, _ := flat.GetRecord("database")
+	flatDB.Set("host", "modified")
+	defaultHost, _ := defaults.GetStringByPath("database.host")
+	fmt.Printf("   修改 Flatten 结果后 defaults 的 database.host: %s\n", defaultHost)
+
+	// 8. 替换和删除层
+	fmt.Println("\n8. 替换和删除层")
+	config.AddLayer("prod.json", eorm.NewRecord().FromJson(`{"cache": {"ttl": 60}}`))
+	source, _ := config.Source("cache.ttl")
+	fmt.Printf("   替换 prod.json 后 cache.ttl = %s (来自 %s)\n", config.GetString("cache.ttl"), source)
+	config.RemoveLayer("prod.json")
+	source, _ = config.Source("cache.ttl")
+	fmt.Printf("   删除 prod.json 后 cache.ttl = %s (来自 %s)\n", config.GetString("cache.ttl"), source)
+
+	// 9. 上层的普通值覆盖下层子树
+	fmt.Println("\n9. 上层的普通值覆盖下层子树")
+	config.AddLayer("dsn", eorm.NewRecord().Set("database", "postgres://db.prod.internal/shop"))
+	_, ok := config.Get("database.host")
+	_, hasSource := config.Source("database.host")
+	dsnHost, _ := config.Flatten().GetStringByPath("database.host")
+	fmt.Printf("   database = %s\n", config.GetString("database"))
+	fmt.Printf("   Get(\"database.host\") 存在: %v，Source 存在: %v，Flatten 中的值: %q\n", ok, hasSource, dsnHost)
+	config.RemoveLayer("dsn")
+
+	// 10. 错误处理
+	fmt.Println("\n10. 错误处理")
+	if _, ok := config.Source("missing.key"); !ok {
+		fmt.Println("   路径不存在 (预期): Source 返回 false")
+	}
+	if _, err := FromPaths(map[string]interface{}{"a": 1, "a.b": 2}); err != nil {
+		fmt.Printf("   路径冲突 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+// fromFlags 将显式传入的命令行参数转换为一层配置，参数名中的点表示嵌套
+func fromFlags(fs *flag.FlagSet) (*eorm.Record, error) {
+	values := make(map[string]interface{})
+	fs.Visit(func(f *flag.Flag) {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			values[strings.TrimLeft(f.Name, "-")] = getter.Get()
+		} else {
+			values[strings.TrimLeft(f.Name, "-")] = f.Value.String()
+		}
+	})
+	return FromPaths(values)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例20：声明式校验规则
+// 演示按路径声明校验规则，一次返回全部校验失败，并通过通配符校验嵌套 Record 和数组元素
+func main() {
+	fmt.Println("========== Record 声明式校验示例 ==========")
+
+	// 1. 声明规则
+	fmt.Println("\n1. 声明规则")
+	userRules := NewRuleSet().
+		Field("username", Required(), Type(KindString), Length(3, 20), Pattern(`^[a-z][a-z0-9_]*$`)).
+		Field("password", Required(), Length(8, -1)).
+		Field("email", Required(), Email()).
+		Field("age", Type(KindInt), Min(18), Max(120)).
+		Field("role", Enum("admin", "editor", "viewer")).
+		Field("homepage", URL()).
+		Field("address.city", Required()).
+		Field("address.zip", Pattern(`^\d{6}$`).WithMessage("邮编必须是 6 位数字"))
+	fmt.Println("   username、password、email、age、role、homepage、address.city、address.zip")
+
+	// 2. 校验通过
+	fmt.Println("\n2. 校验通过")
+	valid := eorm.NewRecord().FromJson(`{
+		"username": "zhangsan",
+		"password": "p@ssw0rd!",
+		"email": "zhangsan@example.com",
+		"age": 25,
+		"role": "editor",
+		"homepage": "https://zhangsan.dev",
+		"address": {"city": "北京", "zip": "100000"}
+	}`)
+	if errs := userRules.Validate(valid); errs == nil {
+		fmt.Println("   ✅ 全部规则通过")
+	}
+
+	// 3. 一次返回全部校验失败
+	fmt.Println("\n3. 一次返回全部校验失败")
+	invalid := eorm.NewRecord().FromJson(`{
+		"username": "Li",
+		"password": "123",
+		"email": "lisi@",
+		"age": 16.5,
+		"role": "owner",
+		"homepage": "lisi.dev",
+		"address": {"zip": "1000"}
+	}`)
+	for _, v := range userRules.Validate(invalid) {
+		fmt.Printf("   ❌ %-14s [%s] %s\n", v.Path, v.Rule, v.Message)
+	}
+
+	// 4. 通配符校验数组元素
+	fmt.Println("\n4. 通配符校验数组元素")
+	orderRules := NewRuleSet().
+		Field("order_no", Required()).
+		Field("items", Required(), Type(KindArray), Length(1, -1)).
+		Field("items.*.sku", Required(), Pattern(`^SKU-\d+$`)).
+		Field("items.*.quantity", Required(), Type(KindInt), Min(1)).
+		Field("items.*.price", Required(), Min(0)).
+		Field("shipping.*", Required())
+
+	order := eorm.NewRecord().
+		Set("order_no", "SO-1001").
+		Set("items", []*eorm.Record{
+			eorm.NewRecord().Set("sku", "SKU-1").Set("quantity", 2).Set("price", 99.5),
+			eorm.NewRecord().Set("sku", "ABC").Set("quantity", 0).Set("price", -1),
+			eorm.NewRecord().Set("quantity", 1).Set("price", 10),
+		}).
+		Set("shipping", eorm.NewRecord().
+			Set("name", "张三").
+			Set("phone", ""))
+	for _, v := range orderRules.Validate(order) {
+		fmt.Printf("   ❌ %s\n", v)
+	}
+
+	// 5. 路径缺失
+	fmt.Println("\n5. 路径缺失")
+	empty := eorm.NewRecord().Set("order_no", "SO-1002")
+	for _, v := range orderRules.Validate(empty) {
+		fmt.Printf("   ❌ %s\n", v)
+	}
+	fmt.Println("   items 不存在时，items.*.sku 等通配符规则不会展开")
+
+	// 6. 自定义规则
+	fmt.Println("\n6. 自定义规则")
+	reserved := map[string]bool{"root": true, "admin": true}
+	signupRules := NewRuleSet().
+		Field("username", Required(), Custom("reserved", func(v interface{}) error {
+			if reserved[strings.ToLower(eorm.Convert.ToString(v))] {
+				return errors.New("用户名已被保留")
+			}
+			return nil
+		}))
+	for _, name := range []string{"Root", "wangwu"} {
+		errs := signupRules.Validate(eorm.NewRecord().Set("username", name))
+		if errs != nil {
+			fmt.Printf("   %s: ❌ %v\n", name, errs)
+		} else {
+			fmt.Printf("   %s: ✅ 通过\n", name)
+		}
+	}
+
+	// 7. 作为 error 返回
+	fmt.Println("\n7. 作为 error 返回")
+	if err := register(eorm.NewRecord().Set("username", "zhaoliu")); err != nil {
+		var errs ValidationErrors
+		if errors.As(err, &errs) {
+			fmt.Printf("   共 %d 个错误\n", len(errs))
+		}
+		fmt.Printf("   注册失败 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+var registerRules = NewRuleSet().
+	Field("username", Required(), Length(3, 20)).
+	Field("password", Required(), Length(8, -1)).
+	Field("email", Required(), Email())
+
+// register 代替指南中逐个调用 Has 的写法
+func register(record *eorm.Record) error {
+	if errs := registerRules.Validate(record); errs != nil {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// Violation 描述一条校验失败
+type Violation struct {
+	Path    string // 具体的点分路径，数组元素用下标，例如 items.1.price，与示例 21 的 SchemaError.Path 一致
+	Rule    string // 规则名，例如 required、min
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationErrors 是 Validate 返回的全部校验失败，实现了 error 接口
+type ValidationErrors []Violation
+
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, v := range e {
+		parts[i] = v.String()
+	}
+	return "validation: " + strings.Join(parts, "; ")
+}
+
+// Kind 是 Type 规则检查的值类型
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindNumber      // 任意整数或浮点数
+	KindInt         // 整数，或没有小数部分的浮点数（JSON 数字解析为 float64）
+	KindBool
+	KindRecord // 嵌套对象
+	KindArray
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindInt:
+		return "integer"
+	case KindBool:
+		return "boolean"
+	case KindRecord:
+		return "object"
+	}
+	return "array"
+}
+
+// Rule 是作用于单个值的校验规则，通过 Required、Min、Pattern 等函数创建
+// 除 Required 外，路径不存在或值为 nil 时规则不执行
+type Rule struct {
+	name    string
+	message string
+	bail    bool // 失败时跳过同一路径的后续规则
+	check   func(v interface{}) (string, bool)
+}
+
+// WithMessage 替换默认的错误信息
+func (r Rule) WithMessage(message string) Rule {
+	r.message = message
+	return r
+}
+
+// Required 要求路径存在，且值不为 nil 或空字符串
+func Required() Rule {
+	return Rule{name: "required", bail: true, check: func(v interface{}) (string, bool) {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) == "" {
+			return "is required", false
+		}
+		return "", true
+	}}
+}
+
+// Type 要求值为指定类型
+func Type(kind Kind) Rule {
+	return Rule{name: "type", bail: true, check: func(v interface{}) (string, bool) {
+		if kindOf(v, kind) {
+			return "", true
+		}
+		return fmt.Sprintf("must be %s, got %T", kind, v), false
+	}}
+}
+
+// Min 要求数值不小于 n，数字字符串按数值比较
+func Min(n float64) Rule {
+	return Rule{name: "min", check: func(v interface{}) (string, bool) {
+		f, ok := toNumber(v)
+		if !ok {
+			return "must be a number", false
+		}
+		if f < n {
+			return fmt.Sprintf("must be >= %v", n), false
+		}
+		return "", true
+	}}
+}
+
+// Max 要求数值不大于 n，数字字符串按数值比较
+func Max(n float64) Rule {
+	return Rule{name: "max", check: func(v interface{}) (string, bool) {
+		f, ok := toNumber(v)
+		if !ok {
+			return "must be a number", false
+		}
+		if f > n {
+			return fmt.Sprintf("must be <= %v", n), false
+		}
+		return "", true
+	}}
+}
+
+// Length 限制字符串的字符数或数组的元素个数，max 小于 0 表示不限制上限
+func Length(min, max int) Rule {
+	return Rule{name: "length", check: func(v interface{}) (string, bool) {
+		var n int
+		if s, ok := v.(string); ok {
+			n = utf8.RuneCountInString(s)
+		} else if rv := reflect.ValueOf(v); isArray(rv) {
+			n = rv.Len()
+		} else {
+			return "must be a string or array", false
+		}
+		switch {
+		case max < 0 && n < min:
+			return fmt.Sprintf("length must be >= %d", min), false
+		case max >= 0 && (n < min || n > max):
+			return fmt.Sprintf("length must be between %d and %d", min, max), false
+		}
+		return "", true
+	}}
+}
+
+// Pattern 要求字符串匹配正则表达式，表达式无效时 panic，与 regexp.MustCompile 一致
+func Pattern(expr string) Rule {
+	re := regexp.MustCompile(expr)
+	return Rule{name: "pattern", check: func(v interface{}) (string, bool) {
+		s, ok := v.(string)
+		if !ok || !re.MatchString(s) {
+			return fmt.Sprintf("must match pattern %s", expr), false
+		}
+		return "", true
+	}}
+}
+
+// Enum 要求值为给定值之一，按字符串形式比较，因此 1 与 float64(1) 视为相等
+func Enum(values ...interface{}) Rule {
+	allowed := make([]string, len(values))
+	for i, v := range values {
+		allowed[i] = eorm.Convert.ToString(v)
+	}
+	return Rule{name: "enum", check: func(v interface{}) (string, bool) {
+		s := eorm.Convert.ToString(v)
+		for _, a := range allowed {
+			if s == a {
+				return "", true
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", strings.Join(allowed, ", ")), false
+	}}
+}
+
+// Email 要求值为单个邮件地址，不接受 "Name <addr>" 形式
+func Email() Rule {
+	return Rule{name: "email", check: func(v interface{}) (string, bool) {
+		s, ok := v.(string)
+		if ok {
+			if addr, err := mail.ParseAddress(s); err == nil && addr.Address == s {
+				return "", true
+			}
+		}
+		return "must be a valid email address", false
+	}}
+}
+
+// URL 要求值为包含 scheme 和 host 的绝对 URL
+func URL() Rule {
+	return Rule{name: "url", check: func(v interface{}) (string, bool) {
+		s, ok := v.(string)
+		if ok {
+			if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+				return "", true
+			}
+		}
+		return "must be a valid URL", false
+	}}
+}
+
+// Custom 使用自定义函数校验，fn 返回的错误信息作为 Message
+func Custom(name string, fn func(v interface{}) error) Rule {
+	return Rule{name: name, check: func(v interface{}) (string, bool) {
+		if err := fn(v); err != nil {
+			return err.Error(), false
+		}
+		return "", true
+	}}
+}
+
+type field struct {
+	path  string
+	rules []Rule
+}
+
+// RuleSet 是按路径声明的校验规则集合，声明后可以并发用于多个 Record
+//
+// 路径规则：
+//  1. 点号分隔嵌套 Record，例如 address.city
+//  2. * 匹配数组的每个元素或嵌套 Record 的每个键，例如 items.*.price
+//  3. 数字段按数组下标访问，例如 items.0.price
+type RuleSet struct {
+	fields []field
+}
+
+// NewRuleSet 创建空的规则集合
+func NewRuleSet() *RuleSet {
+	return &RuleSet{}
+}
+
+// Field 为路径添加规则，同一路径多次调用时规则追加，支持链式调用
+func (s *RuleSet) Field(path string, rules ...Rule) *RuleSet {
+	for i := range s.fields {
+		if s.fields[i].path == path {
+			s.fields[i].rules = append(s.fields[i].rules, rules...)
+			return s
+		}
+	}
+	s.fields = append(s.fields, field{path: path, rules: rules})
+	return s
+}
+
+// Validate 校验 Record 并返回全部校验失败，全部通过时返回 nil
+// 结果按规则声明顺序排列，通配符展开后按数组下标或键的顺序排列
+func (s *RuleSet) Validate(r *eorm.Record) ValidationErrors {
+	var errs ValidationErrors
+	for _, f := range s.fields {
+		for _, t := range expand(r, f.path) {
+			for _, rule := range f.rules {
+				if !t.present || t.value == nil {
+					if rule.name == "required" {
+						errs = append(errs, violation(t.path, rule, "is required"))
+						break
+					}
+					continue
+				}
+				if msg, ok := rule.check(t.value); !ok {
+					errs = append(errs, violation(t.path, rule, msg))
+					if rule.bail {
+						break
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func violation(path string, rule Rule, msg string) Violation {
+	if rule.message != "" {
+		msg = rule.message
+	}
+	return Violation{Path: path, Rule: rule.name, Message: msg}
+}
+
+type target struct {
+	path    string
+	value   interface{}
+	present bool
+}
+
+// expand 将带通配符的路径展开为具体路径
+// 普通段不存在时返回一个 present 为 false 的目标，以便 Required 报告；通配符没有可匹配的元素时不返回目标
+func expand(r *eorm.Record, path string) []target {
+	targets := []target{{value: r, present: true}}
+	for _, seg := range strings.Split(path, ".") {
+		var next []target
+		for _, t := range targets {
+			if !t.present {
+				if seg != "*" {
+					next = append(next, target{path: joinPath(t.path, seg)})
+				}
+				continue
+			}
+			next = append(next, step(t, seg)...)
+		}
+		targets = next
+	}
+	return targets
+}
+
+func step(t target, seg string) []target {
+	if rec := asRecord(t.value); rec != nil {
+		if seg == "*" {
+			var out []target
+			for _, key := range rec.Keys() {
+				out = append(out, target{path: joinPath(t.path, key), value: rec.Get(key), present: true})
+			}
+			return out
+		}
+		if !rec.Has(seg) {
+			return []target{{path: joinPath(t.path, seg)}}
+		}
+		return []target{{path: joinPath(t.path, seg), value: rec.Get(seg), present: true}}
+	}
+
+	rv := reflect.ValueOf(t.value)
+	if isArray(rv) {
+		if seg == "*" {
+			out := make([]target, rv.Len())
+			for i := range out {
+				out[i] = target{path: joinPath(t.path, strconv.Itoa(i)), value: rv.Index(i).Interface(), present: true}
+			}
+			return out
+		}
+		// Atoi 要求整段都是整数，"1x"、"1.5" 不会被当作下标 1
+		if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < rv.Len() {
+			return []target{{path: joinPath(t.path, strconv.Itoa(i)), value: rv.Index(i).Interface(), present: true}}
+		}
+	}
+	if seg == "*" {
+		return nil
+	}
+	return []target{{path: joinPath(t.path, seg)}}
+}
+
+func kindOf(v interface{}, kind Kind) bool {
+	switch kind {
+	case KindString:
+		_, ok := v.(string)
+		return ok
+	case KindBool:
+		_, ok := v.(bool)
+		return ok
+	case KindRecord:
+		return asRecord(v) != nil
+	case KindArray:
+		return isArray(reflect.ValueOf(v))
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		return kind == KindNumber || (f == math.Trunc(f) && !math.IsInf(f, 0))
+	}
+	return false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	if _, ok := v.(bool); ok {
+		return 0, false
+	}
+	f, err := eorm.Convert.ToFloat64WithError(v)
+	return f, err == nil
+}
+
+func isArray(rv reflect.Value) bool {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	return rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例21：JSON Schema 校验
+// 演示使用合作方发布的 JSON Schema（draft 2020-12）校验 Record，错误路径为点分路径，可以用 ValueAt 读取原值
+func main() {
+	fmt.Println("========== JSON Schema 校验示例 ==========")
+
+	// 1. 通过 FromJson 加载 Schema
+	fmt.Println("\n1. 通过 FromJson 加载 Schema")
+	schema := eorm.NewRecord().FromJson(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["order_no", "customer", "items"],
+		"properties": {
+			"order_no": {"type": "string", "pattern": "^SO-[0-9]+$"},
+			"status": {"enum": ["pending", "paid", "shipped"]},
+			"created_at": {"type": "string", "format": "date-time"},
+			"customer": {"$ref": "#/$defs/customer"},
+			"items": {
+				"type": "array",
+				"minItems": 1,
+				"items": {"$ref": "#/$defs/item"}
+			},
+			"payment": {
+				"oneOf": [
+					{"type": "object", "required": ["card_no"], "properties": {"card_no": {"type": "string", "minLength": 12}}},
+					{"type": "object", "required": ["wallet"], "properties": {"wallet": {"enum": ["alipay", "wechat"]}}}
+				]
+			},
+			"remark": {"anyOf": [{"type": "string", "maxLength": 20}, {"type": "null"}]}
+		},
+		"$defs": {
+			"customer": {
+				"type": "object",
+				"required": ["name", "email"],
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"email": {"type": "string", "format": "email"},
+					"homepage": {"type": "string", "format": "uri"}
+				},
+				"additionalProperties": false
+			},
+			"item": {
+				"type": "object",
+				"required": ["sku", "quantity", "price"],
+				"allOf": [
+					{"properties": {"sku": {"type": "string"}}},
+					{"properties": {"quantity": {"type": "integer", "minimum": 1}}},
+					{"properties": {"price": {"type": "number", "exclusiveMinimum": 0}}}
+				]
+			}
+		}
+	}`)
+	compiled, err := CompileSchema(schema)
+	if err != nil {
+		fmt.Printf("   编译失败: %v\n", err)
+		return
+	}
+	fmt.Println("   ✅ Schema 编译成功")
+
+	// 2. 校验通过
+	fmt.Println("\n2. 校验通过")
+	order := eorm.NewRecord().FromJson(`{
+		"order_no": "SO-1001",
+		"status": "paid",
+		"created_at": "2024-05-01T10:30:00+08:00",
+		"customer": {"name": "张三", "email": "zhangsan@example.com"},
+		"items": [
+			{"sku": "SKU-1", "quantity": 2, "price": 99.5},
+			{"sku": "SKU-2", "quantity": 1, "price": 10}
+		],
+		"payment": {"wallet": "alipay"},
+		"remark": null
+	}`)
+	if err := compiled.Validate(order); err == nil {
+		fmt.Println("   ✅ 校验通过")
+	}
+
+	// 3. 一次返回全部校验失败
+	fmt.Println("\n3. 一次返回全部校验失败")
+	bad := eorm.NewRecord().FromJson(`{
+		"order_no": "1001",
+		"status": "cancelled",
+		"created_at": "2024-05-01 10:30",
+		"customer": {"name": "", "email": "lisi@", "vip": true},
+		"items": [
+			{"sku": "SKU-1", "quantity": 0, "price": 99.5},
+			{"sku": "SKU-2", "quantity": 1.5},
+			{"sku": 3, "quantity": 1, "price": 5}
+		],
+		"payment": {"card_no": "6222", "wallet": "paypal"},
+		"remark": "这是一条很长很长很长很长很长很长很长很长的备注"
+	}`)
+	err = compiled.Validate(bad)
+	var schemaErrs SchemaErrors
+	if errors.As(err, &schemaErrs) {
+		for _, e := range schemaErrs {
+			fmt.Printf("   ❌ %-20s [%s] %s\n", e.Path, e.Keyword, e.Message)
+		}
+	}
+
+	// 4. 按错误路径读取原值
+	fmt.Println("\n4. 按错误路径读取原值")
+	for _, e := range schemaErrs {
+		if e.Keyword == "required" || e.Keyword == "oneOf" {
+			continue
+		}
+		value, _ := ValueAt(bad, e.Path)
+		fmt.Printf("   %-20s = %v\n", e.Path, value)
+	}
+	_, err = bad.GetStringByPath("items.2.sku")
+	fmt.Printf("   GetStringByPath 不支持数组下标 (预期): %v\n", err)
+
+	// 5. 作为 error 返回
+	fmt.Println("\n5. 作为 error 返回")
+	missing := eorm.NewRecord().Set("order_no", "SO-1002")
+	if err := ValidateSchema(missing, schema); err != nil {
+		fmt.Printf("   校验失败 (预期): %v\n", err)
+	}
+
+	// 6. 递归 Schema
+	fmt.Println("\n6. 递归 Schema（$anchor 与 $ref）")
+	treeSchema := eorm.NewRecord().FromJson(`{
+		"$anchor": "node",
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#node"}}
+		}
+	}`)
+	tree := eorm.NewRecord().FromJson(`{
+		"name": "root",
+		"children": [
+			{"name": "a", "children": [{"name": "a1"}, {"title": "a2"}]},
+			{"name": "b"}
+		]
+	}`)
+	if err := ValidateSchema(tree, treeSchema); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+
+	// 7. Schema 本身有误
+	fmt.Println("\n7. Schema 本身有误")
+	brokenRef := eorm.NewRecord().FromJson(`{"properties": {"user": {"$ref": "#/$defs/user"}}}`)
+	if _, err := CompileSchema(brokenRef); err != nil {
+		fmt.Printf("   无法解析的 $ref (预期): %v\n", err)
+	}
+	brokenPattern := eorm.NewRecord().FromJson(`{"properties": {"code": {"pattern": "[a-z"}}}`)
+	if _, err := CompileSchema(brokenPattern); err != nil {
+		fmt.Printf("   无效的 pattern (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,585 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// maxRefDepth 限制在同一个值上连续展开 $ref 的次数，防止 {"$ref": "#"} 这类自引用死循环
+const maxRefDepth = 32
+
+// SchemaError 描述一条校验失败
+// Path 为点分路径，数组元素用下标作为路径段，例如 items.1.sku；根对象的 Path 为空
+// GetStringByPath 不支持数组下标，按 Path 读取原值使用 ValueAt
+type SchemaError struct {
+	Path    string
+	Keyword string // 失败的关键字，例如 required、pattern
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return "record " + e.Message
+	}
+	return fmt.Sprintf("path '%s' %s", e.Path, e.Message)
+}
+
+// ValueAt 按 SchemaError.Path 的写法读取 r 中的值，数字段按数组下标访问，路径不存在时返回 false
+// 与 Validate 一样先按 ToJson 的结果转换为 JSON 值，嵌套对象返回 map[string]interface{}，数组返回 []interface{}
+func ValueAt(r *eorm.Record, path string) (interface{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+	var cur interface{}
+	if err := json.Unmarshal([]byte(r.ToJson()), &cur); err != nil {
+		return nil, false
+	}
+	if path == "" {
+		return cur, true
+	}
+	for _, seg := range strings.Split(path, ".") {
+		switch val := cur.(type) {
+		case map[string]interface{}:
+			v, ok := val[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, false
+			}
+			cur = val[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// SchemaErrors 是一次校验的全部失败，按路径排序
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, err := range e {
+		parts[i] = err.Error()
+	}
+	return "schema: " + strings.Join(parts, "; ")
+}
+
+// Schema 是编译后的 JSON Schema（draft 2020-12 的常用子集），可以并发用于多个 Record
+//
+// 支持的关键字：
+//  1. 类型：type、enum、const
+//  2. 对象：properties、required、additionalProperties、minProperties、maxProperties
+//  3. 数组：items、prefixItems、minItems、maxItems、uniqueItems
+//  4. 字符串：minLength、maxLength、pattern、format（email、uri、date-time、date、time、uuid、ipv4、ipv6、hostname）
+//  5. 数值：minimum、maximum、exclusiveMinimum、exclusiveMaximum、multipleOf
+//  6. 组合：allOf、anyOf、oneOf、not
+//  7. 引用：$ref 指向同一文档内的 JSON Pointer（#/$defs/name）或 $anchor（#name）
+//
+// 不认识的关键字会被忽略，与规范对未知关键字的处理一致
+type Schema struct {
+	root     interface{}
+	anchors  map[string]interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// CompileSchema 编译 Schema，$ref 无法解析或 pattern 无效时返回错误
+// Schema 本身通常通过 eorm.NewRecord().FromJson(...) 加载
+func CompileSchema(schema *eorm.Record) (*Schema, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema: schema is nil")
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(schema.ToJson()), &doc); err != nil {
+		return nil, fmt.Errorf("schema: %v", err)
+	}
+	s := &Schema{
+		root:     doc,
+		anchors:  make(map[string]interface{}),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	if err := s.compile(doc, "#"); err != nil {
+		return nil, err
+	}
+	// anchors 收集完成后再检查引用
+	if err := s.checkRefs(doc, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ValidateSchema 使用 schema 校验 r，全部通过时返回 nil，否则返回 SchemaErrors
+// 同一个 Schema 需要校验多个 Record 时，先 CompileSchema 再调用 Validate
+func ValidateSchema(r, schema *eorm.Record) error {
+	s, err := CompileSchema(schema)
+	if err != nil {
+		return err
+	}
+	return s.Validate(r)
+}
+
+// compile 收集 $anchor 并预编译 pattern
+func (s *Schema) compile(node interface{}, location string) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if anchor, ok := n["$anchor"].(string); ok {
+			s.anchors[anchor] = n
+		}
+		if p, ok := n["pattern"].(string); ok {
+			if err := s.addPattern(p, location+"/pattern"); err != nil {
+				return err
+			}
+		}
+		if props, ok := n["patternProperties"].(map[string]interface{}); ok {
+			for p := range props {
+				if err := s.addPattern(p, location+"/patternProperties"); err != nil {
+					return err
+				}
+			}
+		}
+		for _, key := range sortedKeys(n) {
+			if err := s.compile(n[key], location+"/"+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range n {
+			if err := s.compile(item, location+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) addPattern(p, location string) error {
+	if _, ok := s.patterns[p]; ok {
+		return nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return fmt.Errorf("schema: invalid pattern at %s: %v", location, err)
+	}
+	s.patterns[p] = re
+	return nil
+}
+
+func (s *Schema) checkRefs(node interface{}, location string) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			if _, err := s.resolve(ref); err != nil {
+				return fmt.Errorf("schema: %v at %s", err, location)
+			}
+		}
+		for _, key := range sortedKeys(n) {
+			if err := s.checkRefs(n[key], location+"/"+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range n {
+			if err := s.checkRefs(item, location+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve 解析同一文档内的引用，不支持指向其他文档的 URI
+func (s *Schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref '%s': only references within the same document are supported", ref)
+	}
+	fragment := ref[1:]
+	if fragment == "" {
+		return s.root, nil
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		if anchor, ok := s.anchors[fragment]; ok {
+			return anchor, nil
+		}
+		return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+	}
+	cur := s.root
+	for _, token := range strings.Split(fragment[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			next, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+		}
+	}
+	return cur, nil
+}
+
+// Validate 校验 Record，全部通过时返回 nil，否则返回按路径排序的 SchemaErrors
+// Record 先按 ToJson 的结果转换为 JSON 值再校验，因此 time.Time 按字符串校验，整数与浮点数都是 number
+func (s *Schema) Validate(r *eorm.Record) error {
+	if r == nil {
+		return SchemaErrors{{Keyword: "type", Message: "is nil"}}
+	}
+	var instance interface{}
+	if err := json.Unmarshal([]byte(r.ToJson()), &instance); err != nil {
+		return fmt.Errorf("schema: %v", err)
+	}
+	errs := s.validate(s.root, instance, "", 0)
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+func (s *Schema) validate(node, v interface{}, path string, refDepth int) SchemaErrors {
+	switch n := node.(type) {
+	case bool:
+		if !n {
+			return SchemaErrors{{Path: path, Keyword: "false", Message: "is not allowed"}}
+		}
+		return nil
+	case map[string]interface{}:
+		return s.validateObjectSchema(n, v, path, refDepth)
+	}
+	return nil
+}
+
+func (s *Schema) validateObjectSchema(n map[string]interface{}, v interface{}, path string, refDepth int) SchemaErrors {
+	var errs SchemaErrors
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, SchemaError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// 2020-12 中 $ref 与其他关键字同时生效
+	if ref, ok := n["$ref"].(string); ok {
+		if refDepth >= maxRefDepth {
+			fail("$ref", "exceeds maximum $ref depth %d", maxRefDepth)
+			return errs
+		}
+		target, _ := s.resolve(ref)
+		errs = append(errs, s.validate(target, v, path, refDepth+1)...)
+	}
+
+	if t, ok := n["type"]; ok && !matchesType(t, v) {
+		fail("type", "must be %s, got %s", typeNames(t), jsonType(v))
+		// 类型不匹配时其他关键字的结果没有意义
+		return errs
+	}
+	if enum, ok := n["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equalJSON(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("enum", "must be one of %s", eorm.ToJson(enum))
+		}
+	}
+	if c, ok := n["const"]; ok && !equalJSON(c, v) {
+		fail("const", "must be %s", eorm.ToJson(c))
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		errs = append(errs, s.validateProperties(n, val, path)...)
+	case []interface{}:
+		errs = append(errs, s.validateItems(n, val, path)...)
+	case string:
+		length := len([]rune(val))
+		if min, ok := number(n["minLength"]); ok && float64(length) < min {
+			fail("minLength", "length must be >= %v", min)
+		}
+		if max, ok := number(n["maxLength"]); ok && float64(length) > max {
+			fail("maxLength", "length must be <= %v", max)
+		}
+		if p, ok := n["pattern"].(string); ok && !s.patterns[p].MatchString(val) {
+			fail("pattern", "must match pattern %s", p)
+		}
+		if format, ok := n["format"].(string); ok && !checkFormat(format, val) {
+			fail("format", "must be a valid %s", format)
+		}
+	case float64:
+		if min, ok := number(n["minimum"]); ok && val < min {
+			fail("minimum", "must be >= %v", min)
+		}
+		if max, ok := number(n["maximum"]); ok && val > max {
+			fail("maximum", "must be <= %v", max)
+		}
+		if min, ok := number(n["exclusiveMinimum"]); ok && val <= min {
+			fail("exclusiveMinimum", "must be > %v", min)
+		}
+		if max, ok := number(n["exclusiveMaximum"]); ok && val >= max {
+			fail("exclusiveMaximum", "must be < %v", max)
+		}
+		if m, ok := number(n["multipleOf"]); ok && m > 0 {
+			if q := val / m; math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("multipleOf", "must be a multiple of %v", m)
+			}
+		}
+	}
+
+	if all, ok := n["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			errs = append(errs, s.validate(sub, v, path, refDepth)...)
+		}
+	}
+	if anyOf, ok := n["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if len(s.validate(sub, v, path, refDepth)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("anyOf", "does not match any schema in anyOf")
+		}
+	}
+	if one, ok := n["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, sub := range one {
+			if len(s.validate(sub, v, path, refDepth)) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			fail("oneOf", "does not match any schema in oneOf")
+		case matched > 1:
+			fail("oneOf", "matches %d schemas in oneOf, expected exactly 1", matched)
+		}
+	}
+	if not, ok := n["not"]; ok && len(s.validate(not, v, path, refDepth)) == 0 {
+		fail("not", "must not match the schema in not")
+	}
+	return errs
+}
+
+func (s *Schema) validateProperties(n, obj map[string]interface{}, path string) SchemaErrors {
+	var errs SchemaErrors
+	if required, ok := n["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, ok := obj[key]; !ok {
+				errs = append(errs, SchemaError{Path: joinPath(path, key), Keyword: "required", Message: "is required"})
+			}
+		}
+	}
+	if min, ok := number(n["minProperties"]); ok && float64(len(obj)) < min {
+		errs = append(errs, SchemaError{Path: path, Keyword: "minProperties", Message: fmt.Sprintf("must have at least %v properties", min)})
+	}
+	if max, ok := number(n["maxProperties"]); ok && float64(len(obj)) > max {
+		errs = append(errs, SchemaError{Path: path, Keyword: "maxProperties", Message: fmt.Sprintf("must have at most %v properties", max)})
+	}
+
+	props, _ := n["properties"].(map[string]interface{})
+	patternProps, _ := n["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := n["additionalProperties"]
+	for _, key := range sortedKeys(obj) {
+		child := joinPath(path, key)
+		matched := false
+		if sub, ok := props[key]; ok {
+			matched = true
+			errs = append(errs, s.validate(sub, obj[key], child, 0)...)
+		}
+		for _, p := range sortedKeys(patternProps) {
+			if s.patterns[p].MatchString(key) {
+				matched = true
+				errs = append(errs, s.validate(patternProps[p], obj[key], child, 0)...)
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			errs = append(errs, SchemaError{Path: child, Keyword: "additionalProperties", Message: "is not allowed"})
+			continue
+		}
+		errs = append(errs, s.validate(additional, obj[key], child, 0)...)
+	}
+	return errs
+}
+
+func (s *Schema) validateItems(n map[string]interface{}, arr []interface{}, path string) SchemaErrors {
+	var errs SchemaErrors
+	if min, ok := number(n["minItems"]); ok && float64(len(arr)) < min {
+		errs = append(errs, SchemaError{Path: path, Keyword: "minItems", Message: fmt.Sprintf("must have at least %v items", min)})
+	}
+	if max, ok := number(n["maxItems"]); ok && float64(len(arr)) > max {
+		errs = append(errs, SchemaError{Path: path, Keyword: "maxItems", Message: fmt.Sprintf("must have at most %v items", max)})
+	}
+	if unique, ok := n["uniqueItems"].(bool); ok && unique {
+	outer:
+		for i := range arr {
+			for j := 0; j < i; j++ {
+				if equalJSON(arr[i], arr[j]) {
+					errs = append(errs, SchemaError{Path: path, Keyword: "uniqueItems", Message: fmt.Sprintf("items %d and %d are equal", j, i)})
+					break outer
+				}
+			}
+		}
+	}
+
+	prefix, _ := n["prefixItems"].([]interface{})
+	items, hasItems := n["items"]
+	for i, item := range arr {
+		child := joinPath(path, strconv.Itoa(i))
+		if i < len(prefix) {
+			errs = append(errs, s.validate(prefix[i], item, child, 0)...)
+		} else if hasItems {
+			errs = append(errs, s.validate(items, item, child, 0)...)
+		}
+	}
+	return errs
+}
+
+func matchesType(t, v interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesTypeName(tt, v)
+	case []interface{}:
+		for _, name := range tt {
+			if s, ok := name.(string); ok && matchesTypeName(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesTypeName(name string, v interface{}) bool {
+	actual := jsonType(v)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+// jsonType 返回 JSON 值的类型名，没有小数部分的数值视为 integer
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeNames(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, n := range list {
+			names = append(names, fmt.Sprint(n))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// checkFormat 校验 format，不认识的 format 视为通过
+func checkFormat(format, s string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	}
+	return true
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func equalJSON(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,473 @@
+// Package infer 从样本 Record 推断字段结构，导出为 JSON Schema 或 Go 结构体，示例 23 的 recordgen 也使用它生成代码
+package infer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// JSON 类型名，与 JSON Schema 的 type 一致
+const (
+	typeNull    = "null"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeString  = "string"
+	typeObject  = "object"
+	typeArray   = "array"
+)
+
+// Shape 是从样本中归纳出的一个值的形状
+type Shape struct {
+	// Types 记录每种 JSON 类型出现的次数
+	Types map[string]int
+	// Properties 对象的字段，字段名按字母顺序保存在 Keys 中
+	// FromJson 不保留键的顺序，按字母排序保证每次生成的结果一致
+	Properties map[string]*Shape
+	Keys       []string
+	// Items 数组元素合并后的形状，样本中只有空数组时为 nil
+	Items *Shape
+
+	objects   int // 作为对象出现的次数，用于判断字段是否可选
+	present   int // 作为父对象字段出现的次数
+	dateTimes int // 可以解析为 RFC3339 时间的字符串和 time.Time 的个数
+	strings   int
+	goSlices  map[string]bool // 数组的 Go 类型，例如 []string、[]interface {}
+}
+
+func newShape() *Shape {
+	return &Shape{Types: make(map[string]int), Properties: make(map[string]*Shape), goSlices: make(map[string]bool)}
+}
+
+// Optional 字段在部分样本中不存在
+func (s *Shape) Optional(parent *Shape) bool {
+	return s.present < parent.objects
+}
+
+// Nullable 字段在部分样本中为 null
+func (s *Shape) Nullable() bool {
+	return s.Types[typeNull] > 0
+}
+
+// InferredSchema 是 InferSchema 的结果，可以导出为 JSON Schema 或 Go 结构体定义
+type InferredSchema struct {
+	Root    *Shape
+	Samples int
+}
+
+// InferSchema 遍历样本 Record，合并得到字段类型、是否可选、是否可为 null、数组元素类型以及嵌套结构
+//
+// 合并规则：
+//  1. 字段在部分样本中缺失时为可选字段，值为 nil 时为可为 null
+//  2. 没有小数部分的数值视为 integer，同一字段既有 integer 又有 number 时合并为 number
+//  3. 所有字符串都能解析为 RFC3339 时间时标记为 date-time 格式
+//  4. 数组所有元素的形状合并为一个 items
+func InferSchema(records ...*eorm.Record) *InferredSchema {
+	root := newShape()
+	for _, r := range records {
+		if r == nil {
+			continue
+		}
+		root.observe(r)
+	}
+	root.sortKeys()
+	return &InferredSchema{Root: root, Samples: len(records)}
+}
+
+func (s *Shape) observe(v interface{}) {
+	if rec := asRecord(v); rec != nil {
+		s.Types[typeObject]++
+		s.objects++
+		for _, key := range rec.Keys() {
+			child, ok := s.Properties[key]
+			if !ok {
+				child = newShape()
+				s.Properties[key] = child
+				s.Keys = append(s.Keys, key)
+			}
+			child.present++
+			child.observe(rec.Get(key))
+		}
+		return
+	}
+
+	switch val := v.(type) {
+	case nil:
+		s.Types[typeNull]++
+		return
+	case bool:
+		s.Types[typeBoolean]++
+		return
+	case string:
+		s.Types[typeString]++
+		s.strings++
+		if _, err := time.Parse(time.RFC3339, val); err == nil {
+			s.dateTimes++
+		}
+		return
+	case time.Time:
+		s.Types[typeString]++
+		s.strings++
+		s.dateTimes++
+		return
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.Types[typeInteger]++
+	case reflect.Float32, reflect.Float64:
+		// JSON 中的整数解析为 float64
+		if f := rv.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			s.Types[typeInteger]++
+		} else {
+			s.Types[typeNumber]++
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte 按字符串处理，与 ToJson 的 base64 输出一致
+			s.Types[typeString]++
+			s.strings++
+			return
+		}
+		s.Types[typeArray]++
+		s.goSlices[rv.Type().String()] = true
+		if rv.Len() > 0 && s.Items == nil {
+			s.Items = newShape()
+		}
+		for i := 0; i < rv.Len(); i++ {
+			s.Items.observe(rv.Index(i).Interface())
+		}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			s.Types[typeNull]++
+		} else {
+			s.observe(rv.Elem().Interface())
+		}
+	default:
+		// 无法识别的类型按字符串处理，与 ToJson 的输出一致
+		s.Types[typeString]++
+		s.strings++
+	}
+}
+
+func (s *Shape) sortKeys() {
+	sort.Strings(s.Keys)
+	for _, child := range s.Properties {
+		child.sortKeys()
+	}
+	if s.Items != nil {
+		s.Items.sortKeys()
+	}
+}
+
+// typeNames 返回除 null 外的类型，integer 与 number 同时出现时合并为 number
+func (s *Shape) typeNames() []string {
+	var names []string
+	for _, t := range []string{typeString, typeInteger, typeNumber, typeBoolean, typeObject, typeArray} {
+		if s.Types[t] == 0 {
+			continue
+		}
+		if t == typeInteger && s.Types[typeNumber] > 0 {
+			continue
+		}
+		names = append(names, t)
+	}
+	return names
+}
+
+func (s *Shape) isDateTime() bool {
+	return s.strings > 0 && s.dateTimes == s.strings
+}
+
+// JSONSchema 导出为 JSON Schema（draft 2020-12），返回的 Record 可以直接 ToJson
+func (s *InferredSchema) JSONSchema() *eorm.Record {
+	schema := eorm.NewRecord().Set("$schema", "https://json-schema.org/draft/2020-12/schema")
+	fillSchema(schema, s.Root)
+	return schema
+}
+
+// fillSchema 向 schema 写入 shape 对应的关键字
+func fillSchema(schema *eorm.Record, s *Shape) {
+	types := s.typeNames()
+	if s.Nullable() || len(types) == 0 {
+		types = append(types, typeNull)
+	}
+	if len(types) == 1 {
+		schema.Set("type", types[0])
+	} else {
+		schema.Set("type", types)
+	}
+	if s.Types[typeString] > 0 && s.isDateTime() {
+		schema.Set("format", "date-time")
+	}
+
+	if s.Types[typeObject] > 0 {
+		props := eorm.NewRecord()
+		var required []string
+		for _, key := range s.Keys {
+			child := s.Properties[key]
+			sub := eorm.NewRecord()
+			fillSchema(sub, child)
+			props.Set(key, sub)
+			if !child.Optional(s) {
+				required = append(required, key)
+			}
+		}
+		schema.Set("properties", props)
+		if len(required) > 0 {
+			schema.Set("required", required)
+		}
+	}
+	if s.Types[typeArray] > 0 && s.Items != nil {
+		items := eorm.NewRecord()
+		fillSchema(items, s.Items)
+		schema.Set("items", items)
+	}
+}
+
+// GoStruct 导出为 Go 结构体定义，name 为顶层结构体名
+//
+// 生成的结构体可以直接作为 ToStruct 的目标：
+//  1. 可选或可为 null 的字段使用指针类型
+//  2. ToStruct 不会把嵌套 Record 转换为结构体，嵌套对象的字段类型为 *eorm.Record，
+//     同时生成对应的结构体（例如 OrderCustomer），需要时再对嵌套 Record 调用 ToStruct
+//  3. 对象数组的字段类型为 []*eorm.Record，其他数组保留样本中的 Go 类型，类型不一致时为 []interface{}
+func (s *InferredSchema) GoStruct(name string) (string, error) {
+	g := &goGenerator{names: make(map[string]bool)}
+	g.generate(exportedName(name), s.Root)
+
+	src := g.buf.Bytes()
+	formatted, err := format.Source(src)
+	if err != nil {
+		return string(src), fmt.Errorf("infer: format generated code: %v", err)
+	}
+	return string(formatted), nil
+}
+
+type goGenerator struct {
+	buf   bytes.Buffer
+	names map[string]bool
+}
+
+func (g *goGenerator) generate(name string, s *Shape) {
+	type nested struct {
+		name  string
+		shape *Shape
+	}
+	var pending []nested
+
+	g.names[name] = true
+	fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+	fields := make(map[string]bool)
+	for _, key := range s.Keys {
+		child := s.Properties[key]
+		field := uniqueName(exportedName(key), fields)
+		goType, comment := "", ""
+
+		switch {
+		case child.isObjectOnly():
+			nestedName := uniqueName(name+exportedName(key), g.names)
+			g.names[nestedName] = true
+			goType, comment = "*eorm.Record", " // "+nestedName
+			pending = append(pending, nested{nestedName, child})
+		case child.isArrayOnly() && child.Items != nil && child.Items.isObjectOnly():
+			nestedName := uniqueName(name+exportedName(singular(key)), g.names)
+			g.names[nestedName] = true
+			goType, comment = "[]*eorm.Record", " // []"+nestedName
+			pending = append(pending, nested{nestedName, child.Items})
+		default:
+			goType = child.goType()
+			if (child.Optional(s) || child.Nullable()) && isScalar(goType) {
+				goType = "*" + goType
+			}
+		}
+
+		tag := key
+		if child.Optional(s) {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`%s\n", field, goType, tag, comment)
+	}
+	g.buf.WriteString("}\n")
+
+	for _, n := range pending {
+		g.buf.WriteString("\n")
+		g.generate(n.name, n.shape)
+	}
+}
+
+func (s *Shape) isObjectOnly() bool {
+	names := s.typeNames()
+	return len(names) == 1 && names[0] == typeObject
+}
+
+func (s *Shape) isArrayOnly() bool {
+	names := s.typeNames()
+	return len(names) == 1 && names[0] == typeArray
+}
+
+// goType 返回标量或数组对应的 Go 类型，类型不一致时返回 interface{}
+func (s *Shape) goType() string {
+	names := s.typeNames()
+	if len(names) != 1 {
+		return "interface{}"
+	}
+	switch names[0] {
+	case typeString:
+		if s.isDateTime() {
+			return "time.Time"
+		}
+		return "string"
+	case typeInteger:
+		return "int64"
+	case typeNumber:
+		return "float64"
+	case typeBoolean:
+		return "bool"
+	case typeArray:
+		if len(s.goSlices) == 1 {
+			for t := range s.goSlices {
+				return strings.ReplaceAll(t, "interface {}", "interface{}")
+			}
+		}
+		return "[]interface{}"
+	}
+	return "interface{}"
+}
+
+func isScalar(goType string) bool {
+	switch goType {
+	case "string", "int64", "float64", "bool", "time.Time":
+		return true
+	}
+	return false
+}
+
+// commonInitialisms 与 golint 的常见缩写保持一致
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// exportedName 将 snake_case、kebab-case 或 camelCase 的键名转换为导出的 Go 标识符
+func exportedName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "Field" + name
+	}
+	return name
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// singular 去掉常见的复数后缀，items -> item，addresses -> address
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "sses"), strings.HasSuffix(key, "xes"):
+		return key[:len(key)-2]
+	case strings.HasSuffix(key, "ies"):
+		return key[:len(key)-3] + "y"
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss"):
+		return key[:len(key)-1]
+	}
+	return key
+}
+
+// Summary 按路径列出每个字段的推断结果，便于快速浏览
+func (s *InferredSchema) Summary() []string {
+	var lines []string
+	var walk func(prefix string, parent *Shape)
+	walk = func(prefix string, parent *Shape) {
+		for _, key := range parent.Keys {
+			child := parent.Properties[key]
+			path := joinPath(prefix, key)
+			var flags []string
+			if child.Optional(parent) {
+				flags = append(flags, "optional")
+			}
+			if child.Nullable() {
+				flags = append(flags, "nullable")
+			}
+			types := strings.Join(child.typeNames(), "|")
+			if types == typeString && child.isDateTime() {
+				types += "(date-time)"
+			}
+			if child.isArrayOnly() && child.Items != nil {
+				types = "array<" + strings.Join(child.Items.typeNames(), "|") + ">"
+			}
+			if types == "" {
+				types = typeNull
+			}
+			line := fmt.Sprintf("%s: %s", path, types)
+			if len(flags) > 0 {
+				line += " (" + strings.Join(flags, ", ") + ")"
+			}
+			lines = append(lines, line)
+			if child.Types[typeObject] > 0 {
+				walk(path, child)
+			}
+			if child.Items != nil && child.Items.Types[typeObject] > 0 {
+				walk(path+".*", child.Items)
+			}
+		}
+	}
+	walk("", s.Root)
+	return lines
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+
+	"examples/records/22_schema_inference/infer"
+)
+
+// 示例22：从样本推断 Schema
+// 演示 InferSchema 遍历上游接口返回的样本 Record，推断字段结构并导出为 JSON Schema 和 Go 结构体
+func main() {
+	fmt.Println("========== Schema 推断示例 ==========")
+
+	// 1. 准备样本（上游接口的多次响应）
+	fmt.Println("\n1. 准备样本")
+	samples := []*eorm.Record{
+		eorm.NewRecord().FromJson(`{
+			"order_id": 1001,
+			"status": "paid",
+			"amount": 199,
+			"created_at": "2024-05-01T10:30:00+08:00",
+			"customer": {"id": 1, "name": "张三", "email": "zhangsan@example.com"},
+			"items": [
+				{"sku": "SKU-1", "quantity": 2, "price": 99.5},
+				{"sku": "SKU-2", "quantity": 1, "price": 0}
+			],
+			"tags": ["vip", "first_order"],
+			"coupon": null
+		}`),
+		eorm.NewRecord().FromJson(`{
+			"order_id": 1002,
+			"status": "pending",
+			"amount": 58.8,
+			"created_at": "2024-05-02T08:00:00Z",
+			"customer": {"id": 2, "name": "李四"},
+			"items": [
+				{"sku": "SKU-3", "quantity": 1, "price": 58.8, "gift": true}
+			],
+			"tags": [],
+			"coupon": "SPRING10",
+			"remark": "请尽快发货"
+		}`),
+	}
+	for i, s := range samples {
+		fmt.Printf("   样本 %d: %d 个字段\n", i+1, len(s.Keys()))
+	}
+
+	schema := infer.InferSchema(samples...)
+
+	// 2. 推断结果
+	fmt.Println("\n2. 推断结果")
+	for _, line := range schema.Summary() {
+		fmt.Printf("   %s\n", line)
+	}
+
+	// 3. 导出为 JSON Schema
+	fmt.Println("\n3. 导出为 JSON Schema")
+	jsonSchema := schema.JSONSchema()
+	fmt.Printf("   %s\n", jsonSchema.ToJson())
+	required, _ := jsonSchema.GetSlice("required")
+	fmt.Printf("   required: %v\n", required)
+
+	// 4. 导出为 Go 结构体
+	fmt.Println("\n4. 导出为 Go 结构体")
+	code, err := schema.GoStruct("Order")
+	if err != nil {
+		fmt.Printf("   生成失败: %v\n", err)
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+
+	// 5. 生成的结构体可以作为 ToStruct 的目标
+	fmt.Println("\n5. 生成的结构体可以作为 ToStruct 的目标")
+	var order Order
+	if err := samples[1].ToStruct(&order); err != nil {
+		fmt.Printf("   ❌ ToStruct 失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   OrderID=%d Amount=%.1f CreatedAt=%s\n", order.OrderID, order.Amount, order.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("   Coupon=%s Remark=%s\n", *order.Coupon, *order.Remark)
+	var customer OrderCustomer
+	if err := order.Customer.ToStruct(&customer); err == nil {
+		fmt.Printf("   Customer: ID=%d Name=%s Email=%v\n", customer.ID, customer.Name, customer.Email)
+	}
+	for _, r := range order.Items {
+		var item OrderItem
+		if err := r.ToStruct(&item); err == nil {
+			fmt.Printf("   Item: SKU=%s Quantity=%d Price=%.1f Gift=%v\n", item.Sku, item.Quantity, item.Price, *item.Gift)
+		}
+	}
+
+	// 6. 样本中的 Go 类型
+	fmt.Println("\n6. 使用 Set 构建的样本保留 Go 类型")
+	built := eorm.NewRecord().
+		Set("name", "report").
+		Set("scores", []int{90, 85}).
+		Set("labels", []string{"a", "b"})
+	code, _ = infer.InferSchema(built).GoStruct("report")
+	for _, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
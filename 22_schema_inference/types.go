@@ -0,0 +1,34 @@
+package main
+
+import (
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 以下结构体由 InferSchema(samples...).GoStruct("Order") 生成，samples 为 main.go 中的两个样本
+
+type Order struct {
+	Amount    float64        `json:"amount"`
+	Coupon    *string        `json:"coupon"`
+	CreatedAt time.Time      `json:"created_at"`
+	Customer  *eorm.Record   `json:"customer"` // OrderCustomer
+	Items     []*eorm.Record `json:"items"`    // []OrderItem
+	OrderID   int64          `json:"order_id"`
+	Remark    *string        `json:"remark,omitempty"`
+	Status    string         `json:"status"`
+	Tags      []interface{}  `json:"tags"`
+}
+
+type OrderCustomer struct {
+	Email *string `json:"email,omitempty"`
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+}
+
+type OrderItem struct {
+	Gift     *bool   `json:"gift,omitempty"`
+	Price    float64 `json:"price"`
+	Quantity int64   `json:"quantity"`
+	Sku      string  `json:"sku"`
+}
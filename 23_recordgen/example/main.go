@@ -0,0 +1,92 @@
+package main
+
+//go:generate go run .. -type Order -package main -o models_gen.go ../samples/orders.ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例23：使用 recordgen 生成的结构体
+// models_gen.go 由 recordgen 根据 samples/orders.ndjson 生成，演示生成的类型与 Record 互相转换
+func main() {
+	fmt.Println("========== recordgen 生成结构体示例 ==========")
+
+	// 1. FromStruct：结构体转换为 Record
+	fmt.Println("\n1. FromStruct：结构体转换为 Record")
+	coupon := "SUMMER20"
+	address := eorm.NewRecord().FromStruct(OrderCustomerAddress{City: "杭州", ZipCode: "310000"})
+	order := Order{
+		OrderID:   2001,
+		Status:    "paid",
+		Amount:    128.5,
+		CreatedAt: time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC),
+		Customer:  eorm.NewRecord().FromStruct(OrderCustomer{ID: 7, Name: "赵六", Address: address}),
+		Items: []*eorm.Record{
+			eorm.NewRecord().FromStruct(OrderItem{Sku: "SKU-9", Quantity: 1, Price: 128.5}),
+		},
+		Tags:   []interface{}{"new"},
+		Coupon: &coupon,
+	}
+	record := eorm.NewRecord().FromStruct(order)
+	fmt.Printf("   %v\n", record.ToJson())
+	fmt.Printf("   Remark 为 nil，未写入 Record: Has(\"remark\")=%v\n", record.Has("remark"))
+
+	// 2. ToStruct：Record 转换回结构体
+	fmt.Println("\n2. ToStruct：Record 转换回结构体")
+	var back Order
+	if err := record.ToStruct(&back); err != nil {
+		fmt.Printf("   ❌ ToStruct 失败: %v\n", err)
+		return
+	}
+	var customer OrderCustomer
+	if err := back.Customer.ToStruct(&customer); err != nil {
+		fmt.Printf("   ❌ ToStruct 失败: %v\n", err)
+		return
+	}
+	fmt.Printf("   OrderID=%d Customer.Name=%s Customer.Address.City=%s\n", back.OrderID, customer.Name, customer.Address.GetString("city"))
+	fmt.Printf("   Items[0].sku=%s Coupon=%s CreatedAt=%s\n", back.Items[0].GetString("sku"), *back.Coupon, back.CreatedAt.Format(time.RFC3339))
+	again := eorm.NewRecord().FromStruct(back)
+	if again.ToJson() == record.ToJson() {
+		fmt.Println("   ✅ 往返转换后内容一致")
+	}
+
+	// 3. 加载样本：FromJson 得到的 Record 直接 ToStruct，嵌套 Record 按需转换
+	fmt.Println("\n3. 加载样本")
+	data, err := os.ReadFile("../samples/orders.ndjson")
+	if err != nil {
+		fmt.Printf("   读取样本失败: %v（请在 example 目录下运行）\n", err)
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			fmt.Printf("   解析失败: %v\n", err)
+			return
+		}
+		var o Order
+		if err := eorm.NewRecord().FromJson(string(raw)).ToStruct(&o); err != nil {
+			fmt.Printf("   ❌ ToStruct 失败: %v\n", err)
+			continue
+		}
+		var item OrderItem
+		if err := o.Items[0].ToStruct(&item); err != nil {
+			fmt.Printf("   ❌ ToStruct 失败: %v\n", err)
+			continue
+		}
+		city, _ := o.Customer.GetStringByPath("address.city")
+		remark := "-"
+		if o.Remark != nil {
+			remark = *o.Remark
+		}
+		fmt.Printf("   #%d %-8s %6.1f %s 商品数=%d 首件=%s×%d 备注=%s\n", o.OrderID, o.Status, o.Amount, city, len(o.Items), item.Sku, item.Quantity, remark)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
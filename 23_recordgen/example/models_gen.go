@@ -0,0 +1,41 @@
+// Code generated by recordgen; DO NOT EDIT.
+// Source: ../samples/orders.ndjson (3 samples)
+
+package main
+
+import (
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+type Order struct {
+	Amount    float64        `json:"amount"`
+	Coupon    *string        `json:"coupon"`
+	CreatedAt time.Time      `json:"created_at"`
+	Customer  *eorm.Record   `json:"customer"` // OrderCustomer
+	Items     []*eorm.Record `json:"items"`    // []OrderItem
+	OrderID   int64          `json:"order_id"`
+	Remark    *string        `json:"remark,omitempty"`
+	Status    string         `json:"status"`
+	Tags      []interface{}  `json:"tags"`
+}
+
+type OrderCustomer struct {
+	Address *eorm.Record `json:"address"` // OrderCustomerAddress
+	Email   *string      `json:"email,omitempty"`
+	ID      int64        `json:"id"`
+	Name    string       `json:"name"`
+}
+
+type OrderCustomerAddress struct {
+	City    string `json:"city"`
+	ZipCode string `json:"zip_code"`
+}
+
+type OrderItem struct {
+	Gift     *bool   `json:"gift,omitempty"`
+	Price    float64 `json:"price"`
+	Quantity int64   `json:"quantity"`
+	Sku      string  `json:"sku"`
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例23：recordgen 结构体代码生成器
+// 读取 JSON 或 NDJSON 样本，生成带 json 标签的 Go 结构体，生成的类型可以通过 FromStruct / ToStruct 与 Record 互相转换
+//
+// 用法：
+//
+//	go run . -type Order -package main -o example/models_gen.go samples/orders.ndjson
+//	cat samples/orders.ndjson | go run . -type Order
+func main() {
+	typeName := flag.String("type", "", "顶层结构体名（必填）")
+	pkg := flag.String("package", "main", "生成文件的包名")
+	output := flag.String("o", "", "输出文件，默认输出到标准输出")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法: recordgen -type Name [-package pkg] [-o file] [samples.json|samples.ndjson ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	records, source, err := readInputs(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+	code, err := Generate(records, GenerateOptions{TypeName: *typeName, Package: *pkg, Source: source})
+	if err != nil {
+		fail(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "recordgen: wrote %s from %d samples\n", *output, len(records))
+}
+
+// readInputs 读取所有样本文件，没有参数或参数为 - 时读取标准输入
+func readInputs(paths []string) ([]*eorm.Record, string, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	var all []*eorm.Record
+	for _, path := range paths {
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, "", err
+			}
+			defer f.Close()
+			r = f
+		}
+		records, err := ReadSamples(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %v", path, err)
+		}
+		all = append(all, records...)
+	}
+	return all, strings.Join(paths, ", "), nil
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "recordgen: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,83 @@
+package main
+
+//go:generate go run .. -type Order -o order_view_gen.go ../schemas/order.schema.json
+//go:generate go run .. -type User -o user_view_gen.go ../models/user.go
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例24：使用 accessorgen 生成的视图类型
+// order_view_gen.go 由 schemas/order.schema.json 生成，user_view_gen.go 由 models/user.go 中的结构体生成
+func main() {
+	fmt.Println("========== 类型化访问方法示例 ==========")
+
+	// 1. 包装已有的 Record
+	fmt.Println("\n1. 包装已有的 Record")
+	record := eorm.NewRecord().FromJson(`{
+		"order_id": 1001,
+		"status": "paid",
+		"amount": 199.5,
+		"paid": true,
+		"created_at": "2024-05-01T10:30:00+08:00",
+		"customer": {"id": 1, "name": "张三", "email": "zhangsan@example.com"},
+		"items": [
+			{"sku": "SKU-1", "quantity": 2, "price": 99.5},
+			{"sku": "SKU-2", "quantity": 1, "price": 0.5}
+		],
+		"tags": ["vip"],
+		"metadata": {"channel": "app"},
+		"source": "upstream"
+	}`)
+	order := AsOrderView(record)
+	fmt.Printf("   OrderID=%d Status=%s Amount=%.1f Paid=%v\n", order.OrderID(), order.Status(), order.Amount(), order.Paid())
+	fmt.Printf("   CreatedAt=%s\n", order.CreatedAt().Format(time.RFC3339))
+	fmt.Printf("   Customer.Name=%s Customer.Email=%s\n", order.Customer().Name(), order.Customer().Email())
+	for _, item := range order.Items() {
+		fmt.Printf("   Item: %s x%d @ %.1f\n", item.Sku(), item.Quantity(), item.Price())
+	}
+	fmt.Printf("   Tags=%v Metadata=%v\n", order.Tags(), order.Metadata().ToJson())
+
+	// 2. 未声明的字段通过 Record 访问
+	fmt.Println("\n2. 未声明的字段通过 Record 访问")
+	fmt.Printf("   source=%s\n", order.GetString("source"))
+	fmt.Printf("   HasRemark=%v\n", order.HasRemark())
+
+	// 3. 修改字段
+	fmt.Println("\n3. 修改字段")
+	order.SetStatus("shipped").SetRemark("顺丰")
+	customer := order.Customer().SetName("张三丰")
+	order.SetCustomer(customer)
+	fmt.Printf("   底层 Record: status=%s remark=%s customer.name=%s\n",
+		record.GetString("status"), record.GetString("remark"), order.Customer().Name())
+
+	// 4. 构建新的 Record
+	fmt.Println("\n4. 构建新的 Record")
+	created := NewOrderView().
+		SetOrderID(1002).
+		SetStatus("pending").
+		SetCustomer(NewCustomerView().SetID(2).SetName("李四")).
+		SetItems([]ItemView{
+			NewItemView().SetSku("SKU-3").SetQuantity(1).SetPrice(58.8),
+		}).
+		SetTags([]string{"first_order"})
+	fmt.Printf("   %v\n", created.ToJson())
+
+	// 5. 由结构体生成的视图
+	fmt.Println("\n5. 由结构体生成的视图")
+	user := NewUserView().
+		SetID(1).
+		SetName("王五").
+		SetAge(30).
+		SetProfile(NewProfileView().SetBio("Gopher").SetSkills([]string{"Go", "SQL"})).
+		SetKeysField([]string{"key-1"})
+	fmt.Printf("   %v\n", user.ToJson())
+	fmt.Printf("   Name=%s Skills=%v HasEmail=%v\n", user.Name(), user.Profile().Skills(), user.HasEmail())
+	// keys 与 Record.Keys 重名，生成的方法名为 KeysField，Keys 仍然是 Record 的方法
+	fmt.Printf("   KeysField=%v Keys=%v\n", user.KeysField(), user.Keys())
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
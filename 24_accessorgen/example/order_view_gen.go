@@ -0,0 +1,291 @@
+// Code generated by accessorgen; DO NOT EDIT.
+// Source: ../schemas/order.schema.json
+
+package main
+
+import (
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// OrderView 是 *eorm.Record 的类型化视图：订单
+// 未声明的字段仍然可以通过嵌入的 Record 访问
+type OrderView struct {
+	*eorm.Record
+}
+
+// NewOrderView 创建空的 OrderView
+func NewOrderView() OrderView {
+	return OrderView{eorm.NewRecord()}
+}
+
+// AsOrderView 将已有的 Record 包装为 OrderView，r 为 nil 时使用空 Record
+func AsOrderView(r *eorm.Record) OrderView {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	return OrderView{r}
+}
+
+// Amount 返回 amount
+func (v OrderView) Amount() float64 {
+	return v.Record.GetFloat("amount")
+}
+
+// SetAmount 设置 amount，支持链式调用
+func (v OrderView) SetAmount(value float64) OrderView {
+	v.Record.Set("amount", value)
+	return v
+}
+
+// HasAmount 返回 amount 是否存在
+func (v OrderView) HasAmount() bool {
+	return v.Record.Has("amount")
+}
+
+// CreatedAt 返回 created_at
+func (v OrderView) CreatedAt() time.Time {
+	return v.Record.GetTime("created_at")
+}
+
+// SetCreatedAt 设置 created_at，支持链式调用
+func (v OrderView) SetCreatedAt(value time.Time) OrderView {
+	v.Record.Set("created_at", value)
+	return v
+}
+
+// HasCreatedAt 返回 created_at 是否存在
+func (v OrderView) HasCreatedAt() bool {
+	return v.Record.Has("created_at")
+}
+
+// Customer 返回下单客户
+// 返回的视图修改后需要调用 SetCustomer 写回
+func (v OrderView) Customer() CustomerView {
+	rec, _ := v.Record.GetRecord("customer")
+	return AsCustomerView(rec)
+}
+
+// SetCustomer 设置 customer，支持链式调用
+func (v OrderView) SetCustomer(value CustomerView) OrderView {
+	v.Record.Set("customer", value.Record)
+	return v
+}
+
+// Items 返回 items
+func (v OrderView) Items() []ItemView {
+	recs, _ := v.Record.GetRecords("items")
+	views := make([]ItemView, len(recs))
+	for i, rec := range recs {
+		views[i] = AsItemView(rec)
+	}
+	return views
+}
+
+// SetItems 设置 items，支持链式调用
+func (v OrderView) SetItems(values []ItemView) OrderView {
+	recs := make([]*eorm.Record, len(values))
+	for i, value := range values {
+		recs[i] = value.Record
+	}
+	v.Record.Set("items", recs)
+	return v
+}
+
+// Metadata 返回 metadata
+func (v OrderView) Metadata() *eorm.Record {
+	rec, _ := v.Record.GetRecord("metadata")
+	return rec
+}
+
+// SetMetadata 设置 metadata，支持链式调用
+func (v OrderView) SetMetadata(value *eorm.Record) OrderView {
+	v.Record.Set("metadata", value)
+	return v
+}
+
+// HasMetadata 返回 metadata 是否存在
+func (v OrderView) HasMetadata() bool {
+	return v.Record.Has("metadata")
+}
+
+// OrderID 返回订单号
+func (v OrderView) OrderID() int64 {
+	return v.Record.GetInt64("order_id")
+}
+
+// SetOrderID 设置 order_id，支持链式调用
+func (v OrderView) SetOrderID(value int64) OrderView {
+	v.Record.Set("order_id", value)
+	return v
+}
+
+// Paid 返回 paid
+func (v OrderView) Paid() bool {
+	return v.Record.GetBool("paid")
+}
+
+// SetPaid 设置 paid，支持链式调用
+func (v OrderView) SetPaid(value bool) OrderView {
+	v.Record.Set("paid", value)
+	return v
+}
+
+// HasPaid 返回 paid 是否存在
+func (v OrderView) HasPaid() bool {
+	return v.Record.Has("paid")
+}
+
+// Remark 返回 remark
+func (v OrderView) Remark() string {
+	return v.Record.GetString("remark")
+}
+
+// SetRemark 设置 remark，支持链式调用
+func (v OrderView) SetRemark(value string) OrderView {
+	v.Record.Set("remark", value)
+	return v
+}
+
+// HasRemark 返回 remark 是否存在
+func (v OrderView) HasRemark() bool {
+	return v.Record.Has("remark")
+}
+
+// Status 返回 status
+func (v OrderView) Status() string {
+	return v.Record.GetString("status")
+}
+
+// SetStatus 设置 status，支持链式调用
+func (v OrderView) SetStatus(value string) OrderView {
+	v.Record.Set("status", value)
+	return v
+}
+
+// Tags 返回 tags
+func (v OrderView) Tags() []string {
+	values, _ := v.Record.GetStringSlice("tags")
+	return values
+}
+
+// SetTags 设置 tags，支持链式调用
+func (v OrderView) SetTags(value []string) OrderView {
+	v.Record.Set("tags", value)
+	return v
+}
+
+// HasTags 返回 tags 是否存在
+func (v OrderView) HasTags() bool {
+	return v.Record.Has("tags")
+}
+
+// CustomerView 是 *eorm.Record 的类型化视图：下单客户
+// 未声明的字段仍然可以通过嵌入的 Record 访问
+type CustomerView struct {
+	*eorm.Record
+}
+
+// NewCustomerView 创建空的 CustomerView
+func NewCustomerView() CustomerView {
+	return CustomerView{eorm.NewRecord()}
+}
+
+// AsCustomerView 将已有的 Record 包装为 CustomerView，r 为 nil 时使用空 Record
+func AsCustomerView(r *eorm.Record) CustomerView {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	return CustomerView{r}
+}
+
+// Email 返回 email
+func (v CustomerView) Email() string {
+	return v.Record.GetString("email")
+}
+
+// SetEmail 设置 email，支持链式调用
+func (v CustomerView) SetEmail(value string) CustomerView {
+	v.Record.Set("email", value)
+	return v
+}
+
+// HasEmail 返回 email 是否存在
+func (v CustomerView) HasEmail() bool {
+	return v.Record.Has("email")
+}
+
+// ID 返回 id
+func (v CustomerView) ID() int64 {
+	return v.Record.GetInt64("id")
+}
+
+// SetID 设置 id，支持链式调用
+func (v CustomerView) SetID(value int64) CustomerView {
+	v.Record.Set("id", value)
+	return v
+}
+
+// Name 返回 name
+func (v CustomerView) Name() string {
+	return v.Record.GetString("name")
+}
+
+// SetName 设置 name，支持链式调用
+func (v CustomerView) SetName(value string) CustomerView {
+	v.Record.Set("name", value)
+	return v
+}
+
+// ItemView 是 *eorm.Record 的类型化视图：订单明细
+// 未声明的字段仍然可以通过嵌入的 Record 访问
+type ItemView struct {
+	*eorm.Record
+}
+
+// NewItemView 创建空的 ItemView
+func NewItemView() ItemView {
+	return ItemView{eorm.NewRecord()}
+}
+
+// AsItemView 将已有的 Record 包装为 ItemView，r 为 nil 时使用空 Record
+func AsItemView(r *eorm.Record) ItemView {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	return ItemView{r}
+}
+
+// Price 返回 price
+func (v ItemView) Price() float64 {
+	return v.Record.GetFloat("price")
+}
+
+// SetPrice 设置 price，支持链式调用
+func (v ItemView) SetPrice(value float64) ItemView {
+	v.Record.Set("price", value)
+	return v
+}
+
+// Quantity 返回 quantity
+func (v ItemView) Quantity() int64 {
+	return v.Record.GetInt64("quantity")
+}
+
+// SetQuantity 设置 quantity，支持链式调用
+func (v ItemView) SetQuantity(value int64) ItemView {
+	v.Record.Set("quantity", value)
+	return v
+}
+
+// Sku 返回 sku
+func (v ItemView) Sku() string {
+	return v.Record.GetString("sku")
+}
+
+// SetSku 设置 sku，支持链式调用
+func (v ItemView) SetSku(value string) ItemView {
+	v.Record.Set("sku", value)
+	return v
+}
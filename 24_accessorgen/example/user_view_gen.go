@@ -0,0 +1,179 @@
+// Code generated by accessorgen; DO NOT EDIT.
+// Source: ../models/user.go
+
+package main
+
+import (
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// UserView 是 *eorm.Record 的类型化视图：用户信息
+// 未声明的字段仍然可以通过嵌入的 Record 访问
+type UserView struct {
+	*eorm.Record
+}
+
+// NewUserView 创建空的 UserView
+func NewUserView() UserView {
+	return UserView{eorm.NewRecord()}
+}
+
+// AsUserView 将已有的 Record 包装为 UserView，r 为 nil 时使用空 Record
+func AsUserView(r *eorm.Record) UserView {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	return UserView{r}
+}
+
+// ID 返回 id
+func (v UserView) ID() int64 {
+	return v.Record.GetInt64("id")
+}
+
+// SetID 设置 id，支持链式调用
+func (v UserView) SetID(value int64) UserView {
+	v.Record.Set("id", value)
+	return v
+}
+
+// Name 返回用户名
+func (v UserView) Name() string {
+	return v.Record.GetString("name")
+}
+
+// SetName 设置 name，支持链式调用
+func (v UserView) SetName(value string) UserView {
+	v.Record.Set("name", value)
+	return v
+}
+
+// Email 返回 email
+func (v UserView) Email() string {
+	return v.Record.GetString("email")
+}
+
+// SetEmail 设置 email，支持链式调用
+func (v UserView) SetEmail(value string) UserView {
+	v.Record.Set("email", value)
+	return v
+}
+
+// HasEmail 返回 email 是否存在
+func (v UserView) HasEmail() bool {
+	return v.Record.Has("email")
+}
+
+// Age 返回 age
+func (v UserView) Age() int64 {
+	return v.Record.GetInt64("age")
+}
+
+// SetAge 设置 age，支持链式调用
+func (v UserView) SetAge(value int64) UserView {
+	v.Record.Set("age", value)
+	return v
+}
+
+// Tags 返回 tags
+func (v UserView) Tags() []string {
+	values, _ := v.Record.GetStringSlice("tags")
+	return values
+}
+
+// SetTags 设置 tags，支持链式调用
+func (v UserView) SetTags(value []string) UserView {
+	v.Record.Set("tags", value)
+	return v
+}
+
+// Profile 返回 profile
+// 返回的视图修改后需要调用 SetProfile 写回
+func (v UserView) Profile() ProfileView {
+	rec, _ := v.Record.GetRecord("profile")
+	return AsProfileView(rec)
+}
+
+// SetProfile 设置 profile，支持链式调用
+func (v UserView) SetProfile(value ProfileView) UserView {
+	v.Record.Set("profile", value.Record)
+	return v
+}
+
+// KeysField 返回API 密钥
+func (v UserView) KeysField() []string {
+	values, _ := v.Record.GetStringSlice("keys")
+	return values
+}
+
+// SetKeysField 设置 keys，支持链式调用
+func (v UserView) SetKeysField(value []string) UserView {
+	v.Record.Set("keys", value)
+	return v
+}
+
+// CreatedAt 返回 created_at
+func (v UserView) CreatedAt() time.Time {
+	return v.Record.GetTime("created_at")
+}
+
+// SetCreatedAt 设置 created_at，支持链式调用
+func (v UserView) SetCreatedAt(value time.Time) UserView {
+	v.Record.Set("created_at", value)
+	return v
+}
+
+// ProfileView 是 *eorm.Record 的类型化视图：用户资料
+// 未声明的字段仍然可以通过嵌入的 Record 访问
+type ProfileView struct {
+	*eorm.Record
+}
+
+// NewProfileView 创建空的 ProfileView
+func NewProfileView() ProfileView {
+	return ProfileView{eorm.NewRecord()}
+}
+
+// AsProfileView 将已有的 Record 包装为 ProfileView，r 为 nil 时使用空 Record
+func AsProfileView(r *eorm.Record) ProfileView {
+	if r == nil {
+		r = eorm.NewRecord()
+	}
+	return ProfileView{r}
+}
+
+// Bio 返回 bio
+func (v ProfileView) Bio() string {
+	return v.Record.GetString("bio")
+}
+
+// SetBio 设置 bio，支持链式调用
+func (v ProfileView) SetBio(value string) ProfileView {
+	v.Record.Set("bio", value)
+	return v
+}
+
+// Website 返回 website
+func (v ProfileView) Website() string {
+	return v.Record.GetString("website")
+}
+
+// SetWebsite 设置 website，支持链式调用
+func (v ProfileView) SetWebsite(value string) ProfileView {
+	v.Record.Set("website", value)
+	return v
+}
+
+// Skills 返回 skills
+func (v ProfileView) Skills() []string {
+	values, _ := v.Record.GetStringSlice("skills")
+	return values
+}
+
+// SetSkills 设置 skills，支持链式调用
+func (v ProfileView) SetSkills(value []string) ProfileView {
+	v.Record.Set("skills", value)
+	return v
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// fieldKind 决定访问方法委托给 Record 的哪个方法
+type fieldKind int
+
+const (
+	kindAny     fieldKind = iota // interface{}，委托给 Get
+	kindString                   // GetString
+	kindInt                      // GetInt64
+	kindFloat                    // GetFloat
+	kindBool                     // GetBool
+	kindTime                     // GetTime
+	kindView                     // GetRecord，返回嵌套视图
+	kindViews                    // GetRecords，返回视图切片
+	kindStrings                  // GetStringSlice
+	kindInts                     // GetIntSlice
+	kindSlice                    // GetSlice
+	kindRecord                   // GetRecord，返回 *eorm.Record
+)
+
+// viewDef 描述一个要生成的视图类型
+type viewDef struct {
+	name   string // 视图类型名，例如 OrderView
+	doc    string
+	fields []viewField
+}
+
+type viewField struct {
+	key      string // Record 中的列名
+	name     string // 方法名中使用的字段名，例如 CreatedAt
+	kind     fieldKind
+	nested   *viewDef // kindView、kindViews 的元素视图
+	optional bool     // 可选字段额外生成 HasXxx
+	doc      string
+}
+
+// recordMethods 是 *eorm.Record 的方法名，视图嵌入了 *eorm.Record，生成的方法不能与之重名，
+// 否则会遮蔽 Record 的方法
+var recordMethods = func() map[string]bool {
+	names := map[string]bool{"Record": true}
+	t := reflect.TypeOf(&eorm.Record{})
+	for i := 0; i < t.NumMethod(); i++ {
+		names[t.Method(i).Name] = true
+	}
+	return names
+}()
+
+// resolveNames 为字段分配方法名，与 Record 的方法重名或字段之间重名时追加 Field 后缀
+func (d *viewDef) resolveNames() {
+	used := make(map[string]bool)
+	for i := range d.fields {
+		f := &d.fields[i]
+		name := f.name
+		for recordMethods[name] || recordMethods["Set"+name] || recordMethods["Has"+name] || used[name] {
+			name += "Field"
+		}
+		used[name] = true
+		f.name = name
+	}
+}
+
+// GenerateOptions 控制生成的代码
+type GenerateOptions struct {
+	Package string // 默认 main
+	Source  string // 写入文件头注释的来源说明
+}
+
+// Generate 生成视图类型的 Go 源文件，root 及其所有嵌套视图都会生成
+func Generate(root *viewDef, opts GenerateOptions) ([]byte, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	var defs []*viewDef
+	seen := make(map[*viewDef]bool)
+	var collect func(d *viewDef)
+	collect = func(d *viewDef) {
+		if seen[d] {
+			return
+		}
+		seen[d] = true
+		d.resolveNames()
+		defs = append(defs, d)
+		for _, f := range d.fields {
+			if f.nested != nil {
+				collect(f.nested)
+			}
+		}
+	}
+	collect(root)
+
+	usesTime := false
+	var body bytes.Buffer
+	for _, d := range defs {
+		for _, f := range d.fields {
+			if f.kind == kindTime {
+				usesTime = true
+			}
+		}
+		writeView(&body, d)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by accessorgen; DO NOT EDIT.\n")
+	if opts.Source != "" {
+		fmt.Fprintf(&out, "// Source: %s\n", opts.Source)
+	}
+	fmt.Fprintf(&out, "\npackage %s\n\nimport (\n", pkg)
+	if usesTime {
+		out.WriteString("\t\"time\"\n\n")
+	}
+	out.WriteString("\t\"github.com/zzguang83325/eorm\"\n)\n")
+	out.Write(body.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return out.Bytes(), fmt.Errorf("format generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+func writeView(w *bytes.Buffer, d *viewDef) {
+	v := d.name
+	fmt.Fprintf(w, "\n// %s 是 *eorm.Record 的类型化视图", v)
+	if d.doc != "" {
+		fmt.Fprintf(w, "：%s", d.doc)
+	}
+	fmt.Fprintf(w, "\n// 未声明的字段仍然可以通过嵌入的 Record 访问\ntype %s struct {\n\t*eorm.Record\n}\n", v)
+	fmt.Fprintf(w, "\n// New%s 创建空的 %s\nfunc New%s() %s {\n\treturn %s{eorm.NewRecord()}\n}\n", v, v, v, v, v)
+	fmt.Fprintf(w, "\n// As%s 将已有的 Record 包装为 %s，r 为 nil 时使用空 Record\nfunc As%s(r *eorm.Record) %s {\n\tif r == nil {\n\t\tr = eorm.NewRecord()\n\t}\n\treturn %s{r}\n}\n", v, v, v, v, v)
+
+	for _, f := range d.fields {
+		writeGetter(w, v, f)
+		writeSetter(w, v, f)
+		if f.optional {
+			fmt.Fprintf(w, "\n// Has%s 返回 %s 是否存在\nfunc (v %s) Has%s() bool {\n\treturn v.Record.Has(%q)\n}\n", f.name, f.key, v, f.name, f.key)
+		}
+	}
+}
+
+func writeGetter(w *bytes.Buffer, v string, f viewField) {
+	doc := "返回 " + f.key
+	if f.doc != "" {
+		doc = "返回" + f.doc
+	}
+	fmt.Fprintf(w, "\n// %s %s\n", f.name, doc)
+	switch f.kind {
+	case kindString:
+		fmt.Fprintf(w, "func (v %s) %s() string {\n\treturn v.Record.GetString(%q)\n}\n", v, f.name, f.key)
+	case kindInt:
+		fmt.Fprintf(w, "func (v %s) %s() int64 {\n\treturn v.Record.GetInt64(%q)\n}\n", v, f.name, f.key)
+	case kindFloat:
+		fmt.Fprintf(w, "func (v %s) %s() float64 {\n\treturn v.Record.GetFloat(%q)\n}\n", v, f.name, f.key)
+	case kindBool:
+		fmt.Fprintf(w, "func (v %s) %s() bool {\n\treturn v.Record.GetBool(%q)\n}\n", v, f.name, f.key)
+	case kindTime:
+		fmt.Fprintf(w, "func (v %s) %s() time.Time {\n\treturn v.Record.GetTime(%q)\n}\n", v, f.name, f.key)
+	case kindView:
+		fmt.Fprintf(w, "// 返回的视图修改后需要调用 Set%s 写回\n", f.name)
+		fmt.Fprintf(w, "func (v %s) %s() %s {\n\trec, _ := v.Record.GetRecord(%q)\n\treturn As%s(rec)\n}\n", v, f.name, f.nested.name, f.key, f.nested.name)
+	case kindViews:
+		fmt.Fprintf(w, "func (v %s) %s() []%s {\n\trecs, _ := v.Record.GetRecords(%q)\n\tviews := make([]%s, len(recs))\n\tfor i, rec := range recs {\n\t\tviews[i] = As%s(rec)\n\t}\n\treturn views\n}\n",
+			v, f.name, f.nested.name, f.key, f.nested.name, f.nested.name)
+	case kindStrings:
+		fmt.Fprintf(w, "func (v %s) %s() []string {\n\tvalues, _ := v.Record.GetStringSlice(%q)\n\treturn values\n}\n", v, f.name, f.key)
+	case kindInts:
+		fmt.Fprintf(w, "func (v %s) %s() []int {\n\tvalues, _ := v.Record.GetIntSlice(%q)\n\treturn values\n}\n", v, f.name, f.key)
+	case kindSlice:
+		fmt.Fprintf(w, "func (v %s) %s() []interface{} {\n\tvalues, _ := v.Record.GetSlice(%q)\n\treturn values\n}\n", v, f.name, f.key)
+	case kindRecord:
+		fmt.Fprintf(w, "func (v %s) %s() *eorm.Record {\n\trec, _ := v.Record.GetRecord(%q)\n\treturn rec\n}\n", v, f.name, f.key)
+	default:
+		fmt.Fprintf(w, "func (v %s) %s() interface{} {\n\treturn v.Record.Get(%q)\n}\n", v, f.name, f.key)
+	}
+}
+
+func writeSetter(w *bytes.Buffer, v string, f viewField) {
+	fmt.Fprintf(w, "\n// Set%s 设置 %s，支持链式调用\n", f.name, f.key)
+	switch f.kind {
+	case kindView:
+		fmt.Fprintf(w, "func (v %s) Set%s(value %s) %s {\n\tv.Record.Set(%q, value.Record)\n\treturn v\n}\n", v, f.name, f.nested.name, v, f.key)
+		return
+	case kindViews:
+		fmt.Fprintf(w, "func (v %s) Set%s(values []%s) %s {\n\trecs := make([]*eorm.Record, len(values))\n\tfor i, value := range values {\n\t\trecs[i] = value.Record\n\t}\n\tv.Record.Set(%q, recs)\n\treturn v\n}\n",
+			v, f.name, f.nested.name, v, f.key)
+		return
+	}
+	fmt.Fprintf(w, "func (v %s) Set%s(value %s) %s {\n\tv.Record.Set(%q, value)\n\treturn v\n}\n", v, f.name, goTypeOf(f.kind), v, f.key)
+}
+
+func goTypeOf(kind fieldKind) string {
+	switch kind {
+	case kindString:
+		return "string"
+	case kindInt:
+		return "int64"
+	case kindFloat:
+		return "float64"
+	case kindBool:
+		return "bool"
+	case kindTime:
+		return "time.Time"
+	case kindStrings:
+		return "[]string"
+	case kindInts:
+		return "[]int"
+	case kindSlice:
+		return "[]interface{}"
+	case kindRecord:
+		return "*eorm.Record"
+	}
+	return "interface{}"
+}
+
+// commonInitialisms 与 golint 的常见缩写保持一致
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// exportedName 将 snake_case、kebab-case 或 camelCase 的键名转换为导出的 Go 标识符
+func exportedName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "Field" + name
+	}
+	return name
+}
+
+// singular 去掉常见的复数后缀，items -> item，addresses -> address
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "sses"), strings.HasSuffix(key, "xes"):
+		return key[:len(key)-2]
+	case strings.HasSuffix(key, "ies"):
+		return key[:len(key)-3] + "y"
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss"):
+		return key[:len(key)-1]
+	}
+	return key
+}
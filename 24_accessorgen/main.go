@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 示例24：accessorgen 类型化访问方法生成器
+// 根据 JSON Schema 或 Go 结构体生成包装 *eorm.Record 的视图类型，字段访问方法委托给 GetString、Set、GetRecord 等方法
+//
+// 用法：
+//
+//	go run . -type Order -package main -o example/order_view_gen.go schemas/order.schema.json
+//	go run . -type User -package main -o example/user_view_gen.go models/user.go
+func main() {
+	typeName := flag.String("type", "", "顶层类型名（必填），视图类型名为该名称加 View 后缀")
+	pkg := flag.String("package", "main", "生成文件的包名")
+	output := flag.String("o", "", "输出文件，默认输出到标准输出")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法: accessorgen -type Name [-package pkg] [-o file] schema.json|struct.go\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeName == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	input := flag.Arg(0)
+
+	var def *viewDef
+	var err error
+	if strings.HasSuffix(input, ".go") {
+		def, err = FromGoSource(input, *typeName)
+	} else {
+		var data []byte
+		if data, err = os.ReadFile(input); err == nil {
+			def, err = FromJSONSchema(data, *typeName)
+		}
+	}
+	if err != nil {
+		fail(fmt.Errorf("%s: %v", input, err))
+	}
+
+	code, err := Generate(def, GenerateOptions{Package: *pkg, Source: filepath.ToSlash(input)})
+	if err != nil {
+		fail(err)
+	}
+	if *output == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "accessorgen: wrote %s\n", *output)
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "accessorgen: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// User 用户信息
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"` // 用户名
+	Email     *string   `json:"email,omitempty"`
+	Age       int       `json:"age"`
+	Tags      []string  `json:"tags"`
+	Profile   Profile   `json:"profile"`
+	Keys      []string  `json:"keys"` // API 密钥
+	CreatedAt time.Time `json:"created_at"`
+	password  string
+}
+
+// Profile 用户资料
+type Profile struct {
+	Bio     string   `json:"bio"`
+	Website string   `json:"website"`
+	Skills  []string `json:"skills"`
+}
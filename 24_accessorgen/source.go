@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FromJSONSchema 从 JSON Schema 构建视图定义，typeName 为顶层类型名（不含 View 后缀）
+//
+// 映射规则：
+//  1. string 映射为 GetString，format 为 date-time 时映射为 GetTime
+//  2. integer、number、boolean 分别映射为 GetInt64、GetFloat、GetBool
+//  3. 带 properties 的对象生成嵌套视图，没有 properties 的对象返回 *eorm.Record
+//  4. 元素为对象的数组生成视图切片，元素为 string、integer 的数组分别映射为 GetStringSlice、GetIntSlice
+//  5. 不在 required 中的字段额外生成 HasXxx
+//  6. $ref 指向 #/$defs/name 时使用 name 作为视图名，同一个定义只生成一次
+func FromJSONSchema(data []byte, typeName string) (*viewDef, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %v", err)
+	}
+	b := &schemaBuilder{root: doc, refs: make(map[string]*viewDef)}
+	root, err := b.resolve(doc)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := root["properties"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("root schema must be an object with properties")
+	}
+	return b.build(exportedName(typeName)+"View", root)
+}
+
+type schemaBuilder struct {
+	root map[string]interface{}
+	refs map[string]*viewDef
+}
+
+// resolve 展开 $ref，只支持同一文档内的 JSON Pointer
+func (b *schemaBuilder) resolve(node map[string]interface{}) (map[string]interface{}, error) {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, nil
+		}
+		target, err := b.pointer(ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+	return nil, fmt.Errorf("$ref nesting too deep")
+}
+
+func (b *schemaBuilder) pointer(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref '%s'", ref)
+	}
+	var cur interface{} = b.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref[1:], "/"), "/") {
+		if token == "" {
+			continue
+		}
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+		}
+		cur = m[strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")]
+	}
+	target, ok := cur.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref '%s'", ref)
+	}
+	return target, nil
+}
+
+func (b *schemaBuilder) build(name string, node map[string]interface{}) (*viewDef, error) {
+	def := &viewDef{name: name, doc: stringValue(node["description"])}
+	props, _ := node["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := node["required"].([]interface{}); ok {
+		for _, r := range list {
+			required[stringValue(r)] = true
+		}
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	// JSON 对象没有顺序，按字母排序保证每次生成的结果一致
+	sort.Strings(keys)
+
+	base := strings.TrimSuffix(name, "View")
+	for _, key := range keys {
+		prop, ok := props[key].(map[string]interface{})
+		if !ok {
+			def.fields = append(def.fields, viewField{key: key, name: exportedName(key), optional: !required[key]})
+			continue
+		}
+		field, err := b.field(base, key, prop)
+		if err != nil {
+			return nil, fmt.Errorf("property '%s': %v", key, err)
+		}
+		field.optional = !required[key]
+		def.fields = append(def.fields, field)
+	}
+	return def, nil
+}
+
+func (b *schemaBuilder) field(base, key string, prop map[string]interface{}) (viewField, error) {
+	field := viewField{key: key, name: exportedName(key), doc: stringValue(prop["description"])}
+	node, err := b.resolve(prop)
+	if err != nil {
+		return field, err
+	}
+	if field.doc == "" {
+		field.doc = stringValue(node["description"])
+	}
+
+	switch schemaType(node) {
+	case "string":
+		field.kind = kindString
+		if node["format"] == "date-time" {
+			field.kind = kindTime
+		}
+	case "integer":
+		field.kind = kindInt
+	case "number":
+		field.kind = kindFloat
+	case "boolean":
+		field.kind = kindBool
+	case "object":
+		field.kind = kindRecord
+		if _, ok := node["properties"]; ok {
+			field.kind = kindView
+			field.nested, err = b.nested(prop, base+exportedName(key)+"View", node)
+		}
+	case "array":
+		field.kind = kindSlice
+		items, ok := node["items"].(map[string]interface{})
+		if !ok {
+			break
+		}
+		resolved, err := b.resolve(items)
+		if err != nil {
+			return field, err
+		}
+		switch schemaType(resolved) {
+		case "string":
+			field.kind = kindStrings
+		case "integer":
+			field.kind = kindInts
+		case "object":
+			if _, ok := resolved["properties"]; ok {
+				field.kind = kindViews
+				field.nested, err = b.nested(items, base+exportedName(singular(key))+"View", resolved)
+			}
+		}
+	}
+	return field, err
+}
+
+// nested 构建嵌套视图，通过 $ref 引用的定义按引用路径复用
+func (b *schemaBuilder) nested(original map[string]interface{}, name string, node map[string]interface{}) (*viewDef, error) {
+	ref, isRef := original["$ref"].(string)
+	if isRef {
+		if def, ok := b.refs[ref]; ok {
+			return def, nil
+		}
+		name = exportedName(ref[strings.LastIndex(ref, "/")+1:]) + "View"
+		// 先登记再构建，支持递归引用
+		def := &viewDef{name: name}
+		b.refs[ref] = def
+		built, err := b.build(name, node)
+		if err != nil {
+			return nil, err
+		}
+		*def = *built
+		return def, nil
+	}
+	return b.build(name, node)
+}
+
+// schemaType 返回除 null 外唯一的类型，没有 type 但有 properties 时视为 object
+func schemaType(node map[string]interface{}) string {
+	switch t := node["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s := stringValue(v); s != "null" {
+				types = append(types, s)
+			}
+		}
+		if len(types) == 1 {
+			return types[0]
+		}
+		return ""
+	}
+	if _, ok := node["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// FromGoSource 从 Go 源文件中的结构体定义构建视图，列名的解析规则与 FromStruct / ToStruct 一致：
+// 依次使用 column、db、json 标签，没有标签时使用小写的字段名
+//
+// 指针字段视为可选字段，同一文件中定义的结构体类型生成嵌套视图
+func FromGoSource(path, typeName string) (*viewDef, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	structs := make(map[string]*ast.StructType)
+	docs := make(map[string]string)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				structs[ts.Name.Name] = st
+				doc := ts.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				docs[ts.Name.Name] = firstLine(doc.Text(), ts.Name.Name)
+			}
+		}
+	}
+	if _, ok := structs[typeName]; !ok {
+		return nil, fmt.Errorf("struct %s not found in %s", typeName, path)
+	}
+
+	b := &structBuilder{structs: structs, docs: docs, views: make(map[string]*viewDef)}
+	return b.build(typeName), nil
+}
+
+type structBuilder struct {
+	structs map[string]*ast.StructType
+	docs    map[string]string
+	views   map[string]*viewDef
+}
+
+func (b *structBuilder) build(typeName string) *viewDef {
+	if def, ok := b.views[typeName]; ok {
+		return def
+	}
+	def := &viewDef{name: typeName + "View", doc: b.docs[typeName]}
+	b.views[typeName] = def
+
+	for _, f := range b.structs[typeName].Fields.List {
+		// 匿名字段在 FromStruct 中作为一个整体字段处理，这里跳过
+		if len(f.Names) == 0 {
+			continue
+		}
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key := columnName(f.Tag, ident.Name)
+			if key == "-" {
+				continue
+			}
+			field := viewField{key: key, name: ident.Name, doc: firstLine(f.Doc.Text()+f.Comment.Text(), "")}
+			typ := f.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				field.optional = true
+				typ = star.X
+			}
+			field.kind, field.nested = b.kindOf(typ)
+			def.fields = append(def.fields, field)
+		}
+	}
+	return def
+}
+
+func (b *structBuilder) kindOf(expr ast.Expr) (fieldKind, *viewDef) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return kindString, nil
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return kindInt, nil
+		case "float32", "float64":
+			return kindFloat, nil
+		case "bool":
+			return kindBool, nil
+		}
+		if _, ok := b.structs[t.Name]; ok {
+			return kindView, b.build(t.Name)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			switch pkg.Name + "." + t.Sel.Name {
+			case "time.Time":
+				return kindTime, nil
+			case "eorm.Record":
+				return kindRecord, nil
+			}
+		}
+	case *ast.StarExpr:
+		if kind, nested := b.kindOf(t.X); kind == kindView || kind == kindRecord {
+			return kind, nested
+		}
+	case *ast.MapType:
+		return kindRecord, nil
+	case *ast.ArrayType:
+		elem := t.Elt
+		if star, ok := elem.(*ast.StarExpr); ok {
+			elem = star.X
+		}
+		switch kind, nested := b.kindOf(elem); kind {
+		case kindString:
+			return kindStrings, nil
+		case kindInt:
+			return kindInts, nil
+		case kindView, kindRecord:
+			if nested == nil {
+				// []eorm.Record 或 []map[string]interface{}
+				return kindSlice, nil
+			}
+			return kindViews, nested
+		}
+		return kindSlice, nil
+	}
+	return kindAny, nil
+}
+
+// columnName 与 eorm 解析结构体标签的顺序一致
+func columnName(tag *ast.BasicLit, fieldName string) string {
+	if tag != nil {
+		raw, err := strconv.Unquote(tag.Value)
+		if err == nil {
+			st := reflect.StructTag(raw)
+			for _, key := range []string{"column", "db", "json"} {
+				if v := st.Get(key); v != "" {
+					if i := strings.Index(v, ","); i != -1 {
+						v = v[:i]
+					}
+					if v != "" {
+						return v
+					}
+				}
+			}
+		}
+	}
+	return strings.ToLower(fieldName)
+}
+
+// firstLine 返回注释的第一行，并去掉以 name 开头的前缀，例如 "User 用户信息" -> "用户信息"
+func firstLine(text, name string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if name != "" {
+		line = strings.TrimSpace(strings.TrimPrefix(line, name))
+	}
+	return line
+}
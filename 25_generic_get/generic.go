@@ -0,0 +1,330 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	recordType    = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+	recordPtrType = reflect.TypeOf((*eorm.Record)(nil))
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+)
+
+// Get 读取 key 并转换为 T，key 不存在或转换失败时返回错误
+// 键名与 Record.Get 一样大小写不敏感
+//
+// 转换规则：
+//  1. 基本类型使用 eorm.Convert 的 ToXxxWithError，与 GetInt、GetString 等方法一致；整数转换会检查目标类型的范围
+//  2. time.Time 和 time.Duration 使用 ToTimeWithError、ToDurationWithError
+//  3. 切片和 map[string]V 逐个元素转换，嵌套 Record 可以转换为 map[string]V
+//  4. 结构体通过 ToStruct 转换，*T 先转换为 T 再取地址
+//  5. *eorm.Record 和 interface{} 原样返回
+func Get[T any](r *eorm.Record, key string) (T, error) {
+	var zero T
+	if r == nil {
+		return zero, fmt.Errorf("generic: record is nil")
+	}
+	if !r.Has(key) {
+		return zero, fmt.Errorf("generic: key '%s' not found", key)
+	}
+	return convertAs[T](r.Get(key), key)
+}
+
+// GetByPath 按点分路径读取值并转换为 T，转换规则与 Get 相同
+// 路径中的数字段按数组下标访问，例如 "orders.0.amount"
+func GetByPath[T any](r *eorm.Record, path string) (T, error) {
+	var zero T
+	if r == nil {
+		return zero, fmt.Errorf("generic: record is nil")
+	}
+	v, err := lookupPath(r, path)
+	if err != nil {
+		return zero, err
+	}
+	return convertAs[T](v, path)
+}
+
+// As 将任意值转换为 T，转换规则与 Get 相同，可用于 GetSlice 等方法返回的元素
+func As[T any](v interface{}) (T, error) {
+	return convertAs[T](v, "")
+}
+
+func convertAs[T any](v interface{}, path string) (T, error) {
+	var zero T
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	out, err := convert(v, target, path)
+	if err != nil {
+		return zero, err
+	}
+	return out.Interface().(T), nil
+}
+
+// convertError 记录转换失败的路径，嵌套转换时只在最外层加前缀
+type convertError struct {
+	path string
+	err  error
+}
+
+func (e *convertError) Error() string {
+	if e.path == "" {
+		return "generic: " + e.err.Error()
+	}
+	return fmt.Sprintf("generic: '%s': %v", e.path, e.err)
+}
+
+func (e *convertError) Unwrap() error {
+	return e.err
+}
+
+func fail(path string, format string, args ...interface{}) error {
+	return &convertError{path: path, err: fmt.Errorf(format, args...)}
+}
+
+func convert(v interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	// interface{} 及其他接口类型：值实现了接口时原样返回
+	if t.Kind() == reflect.Interface {
+		if v == nil {
+			return reflect.Zero(t), nil
+		}
+		if reflect.TypeOf(v).Implements(t) {
+			out := reflect.New(t).Elem()
+			out.Set(reflect.ValueOf(v))
+			return out, nil
+		}
+		return reflect.Value{}, fail(path, "%T does not implement %s", v, t)
+	}
+
+	switch t {
+	case recordPtrType:
+		if rec := asRecord(v); rec != nil {
+			return reflect.ValueOf(rec), nil
+		}
+		return reflect.Value{}, fail(path, "cannot convert %T to *eorm.Record", v)
+	case recordType:
+		if rec := asRecord(v); rec != nil {
+			return reflect.ValueOf(rec).Elem(), nil
+		}
+		return reflect.Value{}, fail(path, "cannot convert %T to eorm.Record", v)
+	}
+
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fail(path, "cannot convert nil to %s", t)
+	}
+	if src := reflect.ValueOf(v); src.Type() == t {
+		return src, nil
+	}
+
+	switch t {
+	case timeType:
+		tm, err := eorm.Convert.ToTimeWithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		return reflect.ValueOf(tm), nil
+	case durationType:
+		d, err := eorm.Convert.ToDurationWithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := eorm.Convert.ToBoolWithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := eorm.Convert.ToInt64WithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		if out.OverflowInt(n) {
+			return reflect.Value{}, fail(path, "value %d overflows %s", n, t)
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, err := eorm.Convert.ToInt64WithError(v); err == nil && n < 0 {
+			return reflect.Value{}, fail(path, "value %d overflows %s", n, t)
+		}
+		n, err := eorm.Convert.ToUint64WithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		if out.OverflowUint(n) {
+			return reflect.Value{}, fail(path, "value %d overflows %s", n, t)
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := eorm.Convert.ToFloat64WithError(v)
+		if err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		if out.OverflowFloat(f) {
+			return reflect.Value{}, fail(path, "value %v overflows %s", f, t)
+		}
+		out.SetFloat(f)
+	case reflect.String:
+		var s string
+		var err error
+		if rec := asRecord(v); rec != nil {
+			s = rec.ToJson()
+		} else if s, err = eorm.Convert.ToStringWithError(v); err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		out.SetString(s)
+	case reflect.Ptr:
+		elem, err := convert(v, t.Elem(), path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Slice:
+		return convertSlice(v, t, path)
+	case reflect.Map:
+		return convertMap(v, t, path)
+	case reflect.Struct:
+		rec := asRecord(v)
+		if rec == nil {
+			return reflect.Value{}, fail(path, "cannot convert %T to %s", v, t)
+		}
+		ptr := reflect.New(t)
+		if err := eorm.ToStruct(rec, ptr.Interface()); err != nil {
+			return reflect.Value{}, fail(path, "%v", err)
+		}
+		return ptr.Elem(), nil
+	default:
+		return reflect.Value{}, fail(path, "unsupported target type %s", t)
+	}
+	return out, nil
+}
+
+func convertSlice(v interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	src := reflect.ValueOf(v)
+	if s, ok := v.(string); ok && t.Elem().Kind() == reflect.Uint8 {
+		return reflect.ValueOf([]byte(s)).Convert(t), nil
+	}
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return reflect.Value{}, fail(path, "cannot convert %T to %s", v, t)
+	}
+	out := reflect.MakeSlice(t, src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		elem, err := convert(src.Index(i).Interface(), t.Elem(), indexPath(path, i))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Index(i).Set(elem)
+	}
+	return out, nil
+}
+
+func convertMap(v interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	if t.Key().Kind() != reflect.String {
+		return reflect.Value{}, fail(path, "unsupported map key type %s", t.Key())
+	}
+	out := reflect.MakeMap(t)
+	set := func(key string, value interface{}) error {
+		elem, err := convert(value, t.Elem(), joinPath(path, key))
+		if err != nil {
+			return err
+		}
+		out.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		return nil
+	}
+
+	if rec := asRecord(v); rec != nil {
+		for _, key := range rec.Keys() {
+			if err := set(key, rec.Get(key)); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+		return out, nil
+	}
+	src := reflect.ValueOf(v)
+	if src.Kind() != reflect.Map || src.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, fail(path, "cannot convert %T to %s", v, t)
+	}
+	iter := src.MapRange()
+	for iter.Next() {
+		if err := set(iter.Key().String(), iter.Value().Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return out, nil
+}
+
+// lookupPath 按点分路径查找值，Record 键名大小写不敏感，数字段按数组下标访问
+func lookupPath(r *eorm.Record, path string) (interface{}, error) {
+	if path == "" {
+		return nil, fmt.Errorf("generic: path cannot be empty")
+	}
+	var cur interface{} = r
+	walked := ""
+	for _, seg := range strings.Split(path, ".") {
+		if rec := asRecord(cur); rec != nil {
+			if !rec.Has(seg) {
+				return nil, fmt.Errorf("generic: path '%s' not found at part '%s'", path, seg)
+			}
+			cur = rec.Get(seg)
+			walked = joinPath(walked, seg)
+			continue
+		}
+		rv := reflect.ValueOf(cur)
+		if cur != nil && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= rv.Len() {
+				return nil, fmt.Errorf("generic: path '%s' index '%s' out of range at '%s'", path, seg, walked)
+			}
+			cur = rv.Index(i).Interface()
+			walked = joinPath(walked, seg)
+			continue
+		}
+		return nil, fmt.Errorf("generic: path '%s' cannot descend into %T at '%s'", path, cur, walked)
+	}
+	return cur, nil
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+// indexPath 与 GetByPath 的路径格式一致，数组下标也用点分隔，例如 orders.0
+func indexPath(prefix string, i int) string {
+	return joinPath(prefix, strconv.Itoa(i))
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+type Address struct {
+	City    string `json:"city"`
+	ZipCode string `json:"zip_code"`
+}
+
+// 示例25：泛型取值
+// 演示 Get[T]、GetByPath[T] 和 As[T]，覆盖 GetXxx 系列方法之外的类型
+func main() {
+	fmt.Println("========== 泛型取值示例 ==========")
+
+	record := eorm.NewRecord().FromJson(`{
+		"port": 8080,
+		"ratio": 0.75,
+		"enabled": "true",
+		"scores": [90, 85.5, 77],
+		"ids": ["1", "2", "3"],
+		"stock": {"apple": 10, "banana": 25},
+		"address": {"city": "上海", "zip_code": "200000"},
+		"timeout": "1m30s",
+		"created_at": "2024-05-01T10:30:00+08:00",
+		"orders": [
+			{"id": 1, "amount": 99.5, "items": [{"sku": "SKU-1"}]},
+			{"id": 2, "amount": 58.8, "items": [{"sku": "SKU-2"}, {"sku": "SKU-3"}]}
+		]
+	}`)
+
+	// 1. 基本类型
+	fmt.Println("\n1. 基本类型")
+	port, _ := Get[uint16](record, "port")
+	ratio, _ := Get[float32](record, "ratio")
+	enabled, _ := Get[bool](record, "enabled")
+	portStr, _ := Get[string](record, "port")
+	fmt.Printf("   port: %v (%T)\n", port, port)
+	fmt.Printf("   ratio: %v (%T)\n", ratio, ratio)
+	fmt.Printf("   enabled: %v (%T，字符串 \"true\" 转换为 bool)\n", enabled, enabled)
+	fmt.Printf("   port 作为 string: %q\n", portStr)
+
+	// 2. 切片和 map
+	fmt.Println("\n2. 切片和 map")
+	scores, _ := Get[[]float64](record, "scores")
+	ids, _ := Get[[]int64](record, "ids")
+	stock, _ := Get[map[string]int](record, "stock")
+	fmt.Printf("   scores: %v (%T)\n", scores, scores)
+	fmt.Printf("   ids: %v (%T)\n", ids, ids)
+	fmt.Printf("   stock: %v (%T)\n", stock, stock)
+
+	// 3. 时间
+	fmt.Println("\n3. 时间")
+	timeout, _ := Get[time.Duration](record, "timeout")
+	createdAt, _ := Get[time.Time](record, "created_at")
+	fmt.Printf("   timeout: %v\n", timeout)
+	fmt.Printf("   created_at: %s\n", createdAt.Format(time.RFC3339))
+
+	// 4. 结构体（通过 ToStruct 转换）
+	fmt.Println("\n4. 结构体")
+	addr, err := Get[Address](record, "address")
+	if err == nil {
+		fmt.Printf("   Address: %+v\n", addr)
+	}
+	addrPtr, _ := Get[*Address](record, "address")
+	fmt.Printf("   *Address: %+v\n", *addrPtr)
+
+	// 5. 按路径取值
+	fmt.Println("\n5. 按路径取值")
+	city, _ := GetByPath[string](record, "address.city")
+	amount, _ := GetByPath[float64](record, "orders.1.amount")
+	sku, _ := GetByPath[string](record, "orders.1.items.0.sku")
+	order, _ := GetByPath[*eorm.Record](record, "orders.0")
+	fmt.Printf("   address.city: %s\n", city)
+	fmt.Printf("   orders.1.amount: %v\n", amount)
+	fmt.Printf("   orders.1.items.0.sku: %s\n", sku)
+	fmt.Printf("   orders.0: %s\n", order.ToJson())
+
+	// 6. As 转换任意值
+	fmt.Println("\n6. As 转换任意值")
+	raw, _ := record.GetSlice("scores")
+	for _, v := range raw {
+		n, err := As[int](v)
+		fmt.Printf("   As[int](%v) = %d, err=%v\n", v, n, err)
+	}
+
+	// 7. 错误处理
+	fmt.Println("\n7. 错误处理")
+	if _, err := Get[int](record, "missing"); err != nil {
+		fmt.Printf("   键不存在 (预期): %v\n", err)
+	}
+	if _, err := Get[int8](record, "port"); err != nil {
+		fmt.Printf("   超出范围 (预期): %v\n", err)
+	}
+	if _, err := Get[[]int](record, "address"); err != nil {
+		fmt.Printf("   类型不匹配 (预期): %v\n", err)
+	}
+	if _, err := Get[map[string]int](eorm.NewRecord().FromJson(`{"m": {"a": 1, "b": "x"}}`), "m"); err != nil {
+		fmt.Printf("   元素转换失败 (预期): %v\n", err)
+	}
+	if _, err := GetByPath[[]int](eorm.NewRecord().FromJson(`{"orders": [{"ids": [1, "x"]}]}`), "orders.0.ids"); err != nil {
+		fmt.Printf("   切片元素转换失败 (预期): %v\n", err)
+	}
+	if _, err := GetByPath[string](record, "orders.5.amount"); err != nil {
+		fmt.Printf("   下标越界 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
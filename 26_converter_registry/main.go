@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// Money 以分为单位保存金额，代替 decimal.Decimal 演示定点数类型
+type Money int64
+
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// ParseMoney 解析 "12.34" 格式的金额，不经过 float64，避免精度损失
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("money %q has more than 2 decimal places", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid money %q", s)
+	}
+	if neg {
+		n = -n
+	}
+	return Money(n), nil
+}
+
+// UUID 代替 uuid.UUID 演示数组类型
+type UUID [16]byte
+
+func (u UUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// ParseUUID 解析带连字符的 UUID
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		return u, fmt.Errorf("invalid uuid %q", s)
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// Status 是自定义枚举，数据库中保存为字符串
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusPaid
+	StatusShipped
+)
+
+var statusNames = []string{"pending", "paid", "shipped"}
+
+func (s Status) String() string {
+	if int(s) < len(statusNames) {
+		return statusNames[s]
+	}
+	return "unknown"
+}
+
+type Order struct {
+	ID       UUID   `column:"id"`
+	Amount   Money  `column:"amount"`
+	Status   Status `column:"status"`
+	ClientIP net.IP `column:"client_ip"`
+	Remark   string `column:"remark"`
+	Discount *Money `column:"discount"`
+}
+
+func registerConverters() {
+	Register(DefaultRegistry,
+		func(m Money) (interface{}, error) { return m.String(), nil },
+		func(p interface{}) (Money, error) { return ParseMoney(eorm.Convert.ToString(p)) })
+	Register(DefaultRegistry,
+		func(u UUID) (interface{}, error) { return u.String(), nil },
+		func(p interface{}) (UUID, error) { return ParseUUID(eorm.Convert.ToString(p)) })
+	Register(DefaultRegistry,
+		func(s Status) (interface{}, error) { return s.String(), nil },
+		func(p interface{}) (Status, error) {
+			name := eorm.Convert.ToString(p)
+			for i, n := range statusNames {
+				if n == name {
+					return Status(i), nil
+				}
+			}
+			return 0, fmt.Errorf("unknown status %q", name)
+		})
+	// net.IP 是切片，默认的往返转换已经会复制底层数组，这里显式提供 Clone 演示 RegisterConverter
+	DefaultRegistry.RegisterConverter(reflectTypeOf[net.IP](), Converter{
+		ToPrimitive: func(v interface{}) (interface{}, error) { return v.(net.IP).String(), nil },
+		FromPrimitive: func(p interface{}) (interface{}, error) {
+			ip := net.ParseIP(eorm.Convert.ToString(p))
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip %v", p)
+			}
+			return ip, nil
+		},
+		Clone: func(v interface{}) interface{} {
+			return append(net.IP(nil), v.(net.IP)...)
+		},
+	})
+}
+
+// 示例26：可插拔的类型转换器
+// 演示为 Money、UUID、枚举和 net.IP 注册转换器，GetString、Get、ToJson、ToStruct、FromStruct、DeepClone 都使用同一套转换规则
+func main() {
+	fmt.Println("========== 类型转换器示例 ==========")
+
+	registerConverters()
+	id, _ := ParseUUID("6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b6c7a10")
+	record := eorm.NewRecord().
+		Set("id", id).
+		Set("amount", Money(19950)).
+		Set("status", StatusPaid).
+		Set("client_ip", net.ParseIP("192.168.1.10")).
+		Set("remark", "加急")
+
+	// 1. GetString
+	fmt.Println("\n1. GetString")
+	fmt.Printf("   Record.GetString(amount): %s\n", record.GetString("amount"))
+	fmt.Printf("   GetString(amount): %s\n", GetString(record, "amount"))
+	fmt.Printf("   GetString(id): %s\n", GetString(record, "id"))
+	fmt.Printf("   GetString(status): %s\n", GetString(record, "status"))
+	fmt.Printf("   GetString(client_ip): %s\n", GetString(record, "client_ip"))
+
+	// 2. Get[T]
+	fmt.Println("\n2. Get[T]")
+	raw := eorm.NewRecord().FromJson(`{"amount": "58.80", "status": "shipped", "id": "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b6c7a10"}`)
+	amount, _ := Get[Money](DefaultRegistry, raw, "amount")
+	status, _ := Get[Status](DefaultRegistry, raw, "status")
+	parsedID, _ := Get[UUID](DefaultRegistry, raw, "id")
+	fmt.Printf("   Money: %s (%d 分)\n", amount, int64(amount))
+	fmt.Printf("   Status: %s (%d)\n", status, int(status))
+	fmt.Printf("   UUID 相同: %v\n", parsedID == id)
+	name, _ := Get[string](DefaultRegistry, record, "status")
+	fmt.Printf("   Get[string](status): %s\n", name)
+
+	// 3. ToJson
+	fmt.Println("\n3. ToJson")
+	fmt.Printf("   Record.ToJson: %s\n", record.ToJson())
+	out, _ := ToJson(record)
+	fmt.Printf("   ToJson: %s\n", out)
+
+	// 4. ToStruct
+	fmt.Println("\n4. ToStruct")
+	row := eorm.NewRecord().FromJson(`{
+		"id": "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b6c7a10",
+		"amount": "199.50",
+		"status": "paid",
+		"client_ip": "10.0.0.8",
+		"remark": "来自数据库",
+		"discount": "5.00"
+	}`)
+	var order Order
+	if err := ToStruct(row, &order); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	} else {
+		fmt.Printf("   ✅ ID=%s Amount=%s Status=%s ClientIP=%s Remark=%s Discount=%s\n",
+			order.ID, order.Amount, order.Status, order.ClientIP, order.Remark, order.Discount)
+	}
+
+	// 5. FromStruct
+	fmt.Println("\n5. FromStruct")
+	order.Status = StatusShipped
+	fromStruct, _ := FromStruct(order)
+	fmt.Printf("   status=%v (%T) amount=%v (%T)\n",
+		fromStruct.Get("status"), fromStruct.Get("status"), fromStruct.Get("amount"), fromStruct.Get("amount"))
+
+	// 6. DeepClone
+	fmt.Println("\n6. DeepClone")
+	cloned, _ := DeepClone(record)
+	ip := cloned.Get("client_ip").(net.IP)
+	ip[len(ip)-1] = 99
+	fmt.Printf("   修改副本: %s\n", cloned.Get("client_ip"))
+	fmt.Printf("   原 Record: %s\n", record.Get("client_ip"))
+
+	// 7. 单个 Record 的转换器
+	fmt.Println("\n7. 单个 Record 的转换器")
+	// 导出给外部系统时金额使用数值（以分为单位），其他类型回退到全局 Registry
+	export := NewRegistry(DefaultRegistry)
+	Register(export,
+		func(m Money) (interface{}, error) { return int64(m), nil },
+		func(p interface{}) (Money, error) {
+			n, err := eorm.Convert.ToInt64WithError(p)
+			return Money(n), err
+		})
+	scoped := WithRegistry(record, export)
+	scopedJSON, _ := scoped.ToJson()
+	fmt.Printf("   Scoped.ToJson: %s\n", scopedJSON)
+	fmt.Printf("   Scoped.GetString(amount): %s\n", scoped.GetString("amount"))
+	fmt.Printf("   全局 GetString(amount): %s\n", GetString(record, "amount"))
+
+	// 8. 转换失败
+	fmt.Println("\n8. 转换失败")
+	bad := eorm.NewRecord().FromJson(`{"amount": "1.999", "status": "lost"}`)
+	if _, err := Get[Money](DefaultRegistry, bad, "amount"); err != nil {
+		fmt.Printf("   金额精度 (预期): %v\n", err)
+	}
+	if err := ToStruct(bad, &Order{}); err != nil {
+		fmt.Printf("   ToStruct (预期): %v\n", err)
+	}
+	if _, err := Get[int8](DefaultRegistry, eorm.NewRecord().Set("level", 300), "level"); err != nil {
+		fmt.Printf("   整数溢出 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// Converter 描述自定义类型与基本类型（string、数值、bool）之间的转换
+type Converter struct {
+	// ToPrimitive 将自定义类型转换为基本类型，用于 GetString、ToJson、FromStruct
+	ToPrimitive func(v interface{}) (interface{}, error)
+	// FromPrimitive 将基本类型转换为自定义类型，用于 Get[T] 和 ToStruct
+	FromPrimitive func(p interface{}) (interface{}, error)
+	// Clone 深拷贝自定义类型的值，为 nil 时通过 ToPrimitive 和 FromPrimitive 往返转换
+	Clone func(v interface{}) interface{}
+}
+
+// Registry 保存按类型注册的 Converter，可以并发使用
+// 查找时先查当前 Registry，找不到再查 parent，因此可以在全局 Registry 之上为单个 Record 覆盖或补充转换器
+type Registry struct {
+	mu         sync.RWMutex
+	parent     *Registry
+	converters map[reflect.Type]Converter
+}
+
+// DefaultRegistry 是全局 Registry，包级函数 GetString、ToJson 等使用它
+var DefaultRegistry = NewRegistry(nil)
+
+// NewRegistry 创建 Registry，parent 为 nil 时不回退
+func NewRegistry(parent *Registry) *Registry {
+	return &Registry{parent: parent, converters: make(map[reflect.Type]Converter)}
+}
+
+// Register 为 T 注册转换器，重复注册时覆盖
+//
+// 示例：
+//
+//	Register(DefaultRegistry,
+//	    func(m Money) (interface{}, error) { return m.String(), nil },
+//	    func(p interface{}) (Money, error) { return ParseMoney(eorm.Convert.ToString(p)) })
+func Register[T any](reg *Registry, to func(T) (interface{}, error), from func(interface{}) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	reg.set(t, Converter{
+		ToPrimitive: func(v interface{}) (interface{}, error) {
+			return to(v.(T))
+		},
+		FromPrimitive: func(p interface{}) (interface{}, error) {
+			return from(p)
+		},
+	})
+}
+
+// RegisterConverter 为 t 注册完整的 Converter，需要自定义 Clone 时使用
+func (reg *Registry) RegisterConverter(t reflect.Type, c Converter) {
+	reg.set(t, c)
+}
+
+func (reg *Registry) set(t reflect.Type, c Converter) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.converters[t] = c
+}
+
+// Lookup 查找 t 的转换器，找不到时依次查找 parent
+func (reg *Registry) Lookup(t reflect.Type) (Converter, bool) {
+	for r := reg; r != nil; r = r.parent {
+		r.mu.RLock()
+		c, ok := r.converters[t]
+		r.mu.RUnlock()
+		if ok {
+			return c, true
+		}
+	}
+	return Converter{}, false
+}
+
+// toPrimitive 将已注册类型的值转换为基本类型，未注册的类型原样返回
+func (reg *Registry) toPrimitive(v interface{}) (interface{}, bool, error) {
+	if v == nil {
+		return nil, false, nil
+	}
+	c, ok := reg.Lookup(reflect.TypeOf(v))
+	if !ok {
+		return v, false, nil
+	}
+	p, err := c.ToPrimitive(v)
+	return p, true, err
+}
+
+// fromPrimitive 将值转换为已注册的类型 t，值已经是 t 类型时原样返回
+func (reg *Registry) fromPrimitive(v interface{}, t reflect.Type) (interface{}, error) {
+	if v != nil && reflect.TypeOf(v) == t {
+		return v, nil
+	}
+	c, _ := reg.Lookup(t)
+	// 值是另一种已注册类型时先转换为基本类型
+	p, _, err := reg.toPrimitive(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromPrimitive(p)
+}
+
+func (reg *Registry) clone(v interface{}) (interface{}, error) {
+	c, _ := reg.Lookup(reflect.TypeOf(v))
+	if c.Clone != nil {
+		return c.Clone(v), nil
+	}
+	p, err := c.ToPrimitive(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromPrimitive(p)
+}
+
+// GetString 读取 key 并转换为字符串，已注册类型先通过 ToPrimitive 转换，其他类型与 Record.GetString 一致
+func (reg *Registry) GetString(r *eorm.Record, key string) string {
+	p, converted, err := reg.toPrimitive(r.Get(key))
+	if err != nil || !converted {
+		return r.GetString(key)
+	}
+	return eorm.Convert.ToString(p)
+}
+
+// Get 读取 key 并转换为 T
+// T 为已注册类型时通过 FromPrimitive 转换；值为已注册类型而 T 为基本类型时先通过 ToPrimitive 转换
+func Get[T any](reg *Registry, r *eorm.Record, key string) (T, error) {
+	var zero T
+	if !r.Has(key) {
+		return zero, fmt.Errorf("converter: key '%s' not found", key)
+	}
+	v := r.Get(key)
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if _, ok := reg.Lookup(t); ok {
+		out, err := reg.fromPrimitive(v, t)
+		if err != nil {
+			return zero, fmt.Errorf("converter: '%s': %v", key, err)
+		}
+		return out.(T), nil
+	}
+	if typed, ok := v.(T); ok {
+		return typed, nil
+	}
+	p, _, err := reg.toPrimitive(v)
+	if err != nil {
+		return zero, fmt.Errorf("converter: '%s': %v", key, err)
+	}
+	out, err := convertPrimitive(p, t)
+	if err != nil {
+		return zero, fmt.Errorf("converter: '%s': %v", key, err)
+	}
+	return out.Interface().(T), nil
+}
+
+// convertPrimitive 使用 eorm.Convert 将基本类型转换为 t
+func convertPrimitive(p interface{}, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		s, err := eorm.Convert.ToStringWithError(p)
+		if err != nil {
+			return out, err
+		}
+		out.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := eorm.Convert.ToInt64WithError(p)
+		if err != nil {
+			return out, err
+		}
+		if out.OverflowInt(n) {
+			return out, fmt.Errorf("value %d overflows %s", n, t)
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := eorm.Convert.ToUint64WithError(p)
+		if err != nil {
+			return out, err
+		}
+		if out.OverflowUint(n) {
+			return out, fmt.Errorf("value %d overflows %s", n, t)
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := eorm.Convert.ToFloat64WithError(p)
+		if err != nil {
+			return out, err
+		}
+		if out.OverflowFloat(f) {
+			return out, fmt.Errorf("value %v overflows %s", f, t)
+		}
+		out.SetFloat(f)
+	case reflect.Bool:
+		b, err := eorm.Convert.ToBoolWithError(p)
+		if err != nil {
+			return out, err
+		}
+		out.SetBool(b)
+	default:
+		if p != nil && reflect.TypeOf(p).AssignableTo(t) {
+			out.Set(reflect.ValueOf(p))
+			return out, nil
+		}
+		return out, fmt.Errorf("cannot convert %T to %s", p, t)
+	}
+	return out, nil
+}
+
+// ToPrimitives 返回新的 Record，其中已注册类型的值递归转换为基本类型，适合序列化或写入数据库
+func (reg *Registry) ToPrimitives(r *eorm.Record) (*eorm.Record, error) {
+	out, err := reg.mapValues(r, "", func(v interface{}, path string) (interface{}, bool, error) {
+		p, converted, err := reg.toPrimitive(v)
+		return p, converted, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*eorm.Record), nil
+}
+
+// ToJson 与 Record.ToJson 相同，已注册类型使用 ToPrimitive 的结果
+func (reg *Registry) ToJson(r *eorm.Record) (string, error) {
+	out, err := reg.ToPrimitives(r)
+	if err != nil {
+		return "", err
+	}
+	return out.ToJson(), nil
+}
+
+// DeepClone 与 Record.DeepClone 相同，已注册类型使用 Converter.Clone 或往返转换拷贝，
+// 避免 net.IP 这类基于切片的类型与原 Record 共享底层数据
+func (reg *Registry) DeepClone(r *eorm.Record) (*eorm.Record, error) {
+	out, err := reg.mapValues(r, "", func(v interface{}, path string) (interface{}, bool, error) {
+		if _, ok := reg.Lookup(reflect.TypeOf(v)); !ok {
+			return v, false, nil
+		}
+		c, err := reg.clone(v)
+		return c, true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*eorm.Record), nil
+}
+
+// mapValues 递归遍历 Record、切片和 map，对叶子值调用 fn，fn 返回 false 时叶子值通过 DeepClone 拷贝
+func (reg *Registry) mapValues(v interface{}, path string, fn func(v interface{}, path string) (interface{}, bool, error)) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if out, handled, err := fn(v, path); err != nil {
+		return nil, fmt.Errorf("converter: '%s': %v", path, err)
+	} else if handled {
+		return out, nil
+	}
+
+	if rec := asRecord(v); rec != nil {
+		result := eorm.NewRecord()
+		for _, key := range rec.Keys() {
+			child, err := reg.mapValues(rec.Get(key), joinPath(path, key), fn)
+			if err != nil {
+				return nil, err
+			}
+			result.Set(key, child)
+		}
+		return result, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if recs, ok := v.([]*eorm.Record); ok {
+			out := make([]*eorm.Record, len(recs))
+			for i, rec := range recs {
+				child, err := reg.mapValues(rec, fmt.Sprintf("%s[%d]", path, i), fn)
+				if err != nil {
+					return nil, err
+				}
+				out[i], _ = child.(*eorm.Record)
+			}
+			return out, nil
+		}
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			child, err := reg.mapValues(rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = child
+		}
+		return out, nil
+	}
+	return eorm.NewRecord().Set("v", v).DeepClone().Get("v"), nil
+}
+
+// FromStruct 与 eorm.ToRecord 相同，已注册类型的字段转换为基本类型，得到的 Record 可以直接 ToJson 或写入数据库
+// 嵌套结构体、结构体切片中的已注册类型同样会转换
+func (reg *Registry) FromStruct(src interface{}) (*eorm.Record, error) {
+	return reg.ToPrimitives(eorm.ToRecord(src))
+}
+
+// ToStruct 与 eorm.ToStruct 相同，已注册类型的字段通过 FromPrimitive 转换
+// 列名的解析规则与 eorm 一致：依次使用 column、db、json 标签，没有标签时使用小写的字段名
+func (reg *Registry) ToStruct(r *eorm.Record, dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("converter: dest must be a pointer to a struct")
+	}
+	sv := rv.Elem()
+	st := sv.Type()
+
+	// 已注册类型的字段单独处理，其余字段交给 eorm.ToStruct
+	custom := make(map[string]int)
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if _, ok := reg.Lookup(ft); ok {
+			if col := columnName(f); col != "-" {
+				custom[strings.ToLower(col)] = i
+			}
+		}
+	}
+
+	rest := eorm.NewRecord()
+	for _, key := range r.Keys() {
+		v := r.Get(key)
+		if i, ok := custom[strings.ToLower(key)]; ok {
+			if v == nil {
+				continue
+			}
+			field := sv.Field(i)
+			ft := field.Type()
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			out, err := reg.fromPrimitive(v, ft)
+			if err != nil {
+				return fmt.Errorf("converter: field %s: %v", st.Field(i).Name, err)
+			}
+			ov := reflect.ValueOf(out)
+			if field.Kind() == reflect.Ptr {
+				ptr := reflect.New(ft)
+				ptr.Elem().Set(ov)
+				ov = ptr
+			}
+			field.Set(ov)
+			continue
+		}
+		// 值是已注册类型而字段是基本类型，例如 Record 中保存 UUID、结构体字段为 string
+		p, _, err := reg.toPrimitive(v)
+		if err != nil {
+			return fmt.Errorf("converter: '%s': %v", key, err)
+		}
+		rest.Set(key, p)
+	}
+	return eorm.ToStruct(rest, dest)
+}
+
+func columnName(f reflect.StructField) string {
+	for _, key := range []string{"column", "db", "json"} {
+		if v := f.Tag.Get(key); v != "" {
+			if i := strings.Index(v, ","); i != -1 {
+				v = v[:i]
+			}
+			if v != "" {
+				return v
+			}
+		}
+	}
+	return strings.ToLower(f.Name)
+}
+
+// Scoped 将 Record 与 Registry 绑定，用于只对单个 Record 生效的转换器
+// Scoped 的方法使用绑定的 Registry，Record 的其他方法仍然可以直接调用
+type Scoped struct {
+	*eorm.Record
+	reg *Registry
+}
+
+// WithRegistry 将 r 与 reg 绑定，通常 reg 以 DefaultRegistry 为 parent 创建
+func WithRegistry(r *eorm.Record, reg *Registry) Scoped {
+	return Scoped{Record: r, reg: reg}
+}
+
+// GetString 使用绑定的 Registry 读取字符串
+func (s Scoped) GetString(key string) string {
+	return s.reg.GetString(s.Record, key)
+}
+
+// ToJson 使用绑定的 Registry 序列化
+func (s Scoped) ToJson() (string, error) {
+	return s.reg.ToJson(s.Record)
+}
+
+// ToStruct 使用绑定的 Registry 转换为结构体
+func (s Scoped) ToStruct(dest interface{}) error {
+	return s.reg.ToStruct(s.Record, dest)
+}
+
+// DeepClone 使用绑定的 Registry 深拷贝，返回的 Scoped 绑定同一个 Registry
+func (s Scoped) DeepClone() (Scoped, error) {
+	r, err := s.reg.DeepClone(s.Record)
+	return Scoped{Record: r, reg: s.reg}, err
+}
+
+// 以下包级函数使用 DefaultRegistry
+
+// GetString 使用 DefaultRegistry 读取字符串
+func GetString(r *eorm.Record, key string) string {
+	return DefaultRegistry.GetString(r, key)
+}
+
+// ToJson 使用 DefaultRegistry 序列化
+func ToJson(r *eorm.Record) (string, error) {
+	return DefaultRegistry.ToJson(r)
+}
+
+// ToStruct 使用 DefaultRegistry 转换为结构体
+func ToStruct(r *eorm.Record, dest interface{}) error {
+	return DefaultRegistry.ToStruct(r, dest)
+}
+
+// FromStruct 使用 DefaultRegistry 从结构体创建 Record
+func FromStruct(src interface{}) (*eorm.Record, error) {
+	return DefaultRegistry.FromStruct(src)
+}
+
+// DeepClone 使用 DefaultRegistry 深拷贝
+func DeepClone(r *eorm.Record) (*eorm.Record, error) {
+	return DefaultRegistry.DeepClone(r)
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func reflectTypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal 是任意精度的十进制数，值为 unscaled × 10^-scale
+// Decimal 不可变，所有运算返回新值；零值表示 0
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// MaxDecimalExponent 是 ParseDecimal 接受的最大指数和小数位数（绝对值）
+// 输入可能来自外部 JSON，不加限制时 "1e-900000000" 会让 String、Round 等分配数百 MB 内存
+const MaxDecimalExponent = 10000
+
+// ParseDecimal 解析十进制字面量，支持 "100.10"、"-0.5"、"1e-3"、"2.5E+2"
+// 保留原始的小数位数，因此 ParseDecimal("100.10").String() == "100.10"
+// 指数或得到的小数位数超过 ±MaxDecimalExponent 时返回错误
+func ParseDecimal(s string) (Decimal, error) {
+	lit := strings.TrimSpace(s)
+	mantissa, exp := lit, 0
+	if i := strings.IndexAny(lit, "eE"); i != -1 {
+		mantissa = lit[:i]
+		var err error
+		if exp, err = strconv.Atoi(lit[i+1:]); err != nil {
+			return Decimal{}, fmt.Errorf("decimal: invalid exponent in %q", s)
+		}
+		if exp > MaxDecimalExponent || exp < -MaxDecimalExponent {
+			return Decimal{}, fmt.Errorf("decimal: exponent %d out of range ±%d", exp, MaxDecimalExponent)
+		}
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := whole + frac
+	if digits == "" || digits == "-" || digits == "+" {
+		return Decimal{}, fmt.Errorf("decimal: invalid literal %q", s)
+	}
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("decimal: invalid literal %q", s)
+	}
+	scale := len(frac) - exp
+	if scale > MaxDecimalExponent || scale < -MaxDecimalExponent {
+		return Decimal{}, fmt.Errorf("decimal: scale %d out of range ±%d", scale, MaxDecimalExponent)
+	}
+	if scale < 0 {
+		n.Mul(n, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: n, scale: scale}, nil
+}
+
+// MustDecimal 与 ParseDecimal 相同，解析失败时 panic，用于常量
+func MustDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromBigInt 将整数转换为小数位数为 0 的 Decimal
+func DecimalFromBigInt(n *big.Int) Decimal {
+	return Decimal{unscaled: new(big.Int).Set(n)}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale 返回小数位数
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// String 返回十进制字面量，不使用科学计数法，保留尾随的 0
+func (d Decimal) String() string {
+	s := d.int().String()
+	if d.scale == 0 {
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if len(s) <= d.scale {
+		s = strings.Repeat("0", d.scale-len(s)+1) + s
+	}
+	s = s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// align 将两个数调整为相同的小数位数
+func align(a, b Decimal) (*big.Int, *big.Int, int) {
+	x, y := new(big.Int).Set(a.int()), new(big.Int).Set(b.int())
+	switch {
+	case a.scale < b.scale:
+		x.Mul(x, pow10(b.scale-a.scale))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y.Mul(y, pow10(a.scale-b.scale))
+	}
+	return x, y, a.scale
+}
+
+// Add 返回 d + o，小数位数取两者的较大值
+func (d Decimal) Add(o Decimal) Decimal {
+	x, y, scale := align(d, o)
+	return Decimal{unscaled: x.Add(x, y), scale: scale}
+}
+
+// Sub 返回 d - o，小数位数取两者的较大值
+func (d Decimal) Sub(o Decimal) Decimal {
+	x, y, scale := align(d, o)
+	return Decimal{unscaled: x.Sub(x, y), scale: scale}
+}
+
+// Mul 返回 d × o，小数位数为两者之和
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Cmp 比较两个数，d < o 返回 -1，相等返回 0，d > o 返回 1；"1.0" 与 "1.00" 相等
+func (d Decimal) Cmp(o Decimal) int {
+	x, y, _ := align(d, o)
+	return x.Cmp(y)
+}
+
+// Round 四舍五入到 scale 位小数（远离 0 舍入）
+func (d Decimal) Round(scale int) Decimal {
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Mul(d.int(), pow10(scale-d.scale)), scale: scale}
+	}
+	q, r := new(big.Int).QuoRem(d.int(), pow10(d.scale-scale), new(big.Int))
+	// |r| × 2 >= 10^(d.scale-scale) 时进位
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(pow10(d.scale-scale)) >= 0 {
+		if d.int().Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: q, scale: scale}
+}
+
+// Rat 返回等值的 *big.Rat
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+}
+
+// Float64 返回最接近的 float64，可能损失精度
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// MarshalJSON 输出数字字面量，Record.ToJson 会原样写出，例如 100.10
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON 接受数字或字符串形式的数字
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseDecimal(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value 实现 driver.Valuer，以字符串传给驱动，写入 DECIMAL/NUMERIC 列时由数据库精确解析
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan 实现 sql.Scanner，DECIMAL 列通常以 []byte 或 string 返回
+func (d *Decimal) Scan(src interface{}) error {
+	parsed, err := toDecimal(src)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
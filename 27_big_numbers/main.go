@@ -0,0 +1,112 @@
+package main
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例27：精确的小数和大数
+// 演示 Decimal、GetDecimal/GetBigInt/GetBigFloat，以及保留数字字面量的 JSON 解析，避免金额经过 float64 产生误差
+func main() {
+	fmt.Println("========== 精确数值示例 ==========")
+
+	payload := `{
+		"order_id": 9007199254740993,
+		"amount": 100.10,
+		"fee": 0.20,
+		"rate": 0.000000000000000000012345,
+		"total_supply": 123456789012345678901234567890,
+		"orders": [{"amount": 19.99}, {"amount": 0.01}]
+	}`
+
+	// 1. Record.FromJson 经过 float64
+	fmt.Println("\n1. Record.FromJson 经过 float64")
+	lossy := eorm.NewRecord().FromJson(payload)
+	fmt.Printf("   order_id: %v\n", lossy.GetInt64("order_id"))
+	fmt.Printf("   amount × 3: %v\n", lossy.GetFloat("amount")*3)
+	fmt.Printf("   total_supply: %v\n", lossy.Get("total_supply"))
+
+	// 2. 保留数字字面量
+	fmt.Println("\n2. FromJsonExact 保留数字字面量")
+	record, err := FromJsonExact(payload)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return
+	}
+	fmt.Printf("   order_id: %v (%T)\n", record.Get("order_id"), record.Get("order_id"))
+	fmt.Printf("   amount: %v (%T)\n", record.Get("amount"), record.Get("amount"))
+	fmt.Printf("   ToJson: %s\n", record.ToJson())
+
+	// 3. GetDecimal / GetBigInt / GetBigFloat
+	fmt.Println("\n3. GetDecimal / GetBigInt / GetBigFloat")
+	amount, _ := GetDecimal(record, "amount")
+	fee, _ := GetDecimal(record, "fee")
+	fmt.Printf("   amount + fee = %s\n", amount.Add(fee))
+	fmt.Printf("   amount × 3 = %s\n", amount.Mul(MustDecimal("3")))
+	supply, _ := GetBigInt(record, "total_supply")
+	fmt.Printf("   total_supply + 1 = %s\n", supply.Add(supply, big.NewInt(1)))
+	rate, _ := GetBigFloat(record, "rate")
+	fmt.Printf("   rate: %s\n", rate.Text('g', 10))
+
+	// 4. 按路径取值
+	fmt.Println("\n4. 按路径取值")
+	sum := Decimal{}
+	for _, path := range []string{"orders.0.amount", "orders.1.amount"} {
+		d, _ := GetDecimalByPath(record, path)
+		sum = sum.Add(d)
+	}
+	fmt.Printf("   orders 合计: %s\n", sum)
+	id, _ := GetBigIntByPath(record, "order_id")
+	fmt.Printf("   order_id: %s\n", id)
+
+	// 5. 在 Record 中保存 Decimal
+	fmt.Println("\n5. 在 Record 中保存 Decimal")
+	invoice := eorm.NewRecord().
+		Set("subtotal", MustDecimal("199.90")).
+		Set("tax", MustDecimal("199.90").Mul(MustDecimal("0.06")).Round(2))
+	fmt.Printf("   ToJson: %s\n", invoice.ToJson())
+
+	// 6. 写入数据库
+	fmt.Println("\n6. 写入数据库")
+	row := PrepareExact(record)
+	row.Remove("orders")
+	for _, key := range []string{"amount", "total_supply", "rate"} {
+		v, err := driver.DefaultParameterConverter.ConvertValue(row.Get(key))
+		fmt.Printf("   驱动收到的 %s: %#v, err=%v\n", key, v, err)
+	}
+	fmt.Println(`   _, err = eorm.InsertRecord("orders", PrepareExact(record))
+   // 读取时 DECIMAL 列以 []byte 或 string 返回，同样用 GetDecimal 精确解析
+   row, _ := eorm.QueryFirst("SELECT amount FROM orders WHERE order_id = ?", id)
+   amount, _ := GetDecimal(row, "amount")`)
+	scanned := eorm.NewRecord().Set("amount", []byte("100.10"))
+	d, _ := GetDecimal(scanned, "amount")
+	fmt.Printf("   []byte(\"100.10\") -> %s\n", d)
+
+	// 7. 错误处理
+	fmt.Println("\n7. 错误处理")
+	if _, err := GetBigInt(record, "amount"); err != nil {
+		fmt.Printf("   小数转整数 (预期): %v\n", err)
+	}
+	if _, err := GetDecimal(record, "orders"); err != nil {
+		fmt.Printf("   类型不匹配 (预期): %v\n", err)
+	}
+	if _, err := GetDecimalByPath(record, "orders.2.amount"); err != nil {
+		fmt.Printf("   下标越界 (预期): %v\n", err)
+	}
+	if _, err := ParseDecimal("12.3.4"); err != nil {
+		fmt.Printf("   非法字面量 (预期): %v\n", err)
+	}
+	if _, err := ParseDecimal("1.5e2x"); err != nil {
+		fmt.Printf("   指数后有多余字符 (预期): %v\n", err)
+	}
+	if huge, err := FromJsonExact(`{"x": 1e-900000000}`); err == nil {
+		if _, err := GetDecimal(huge, "x"); err != nil {
+			fmt.Printf("   指数过大 (预期): %v\n", err)
+		}
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// BigFloatPrec 是 GetBigFloat 使用的精度（二进制位数），约等于 77 位十进制有效数字
+const BigFloatPrec = 256
+
+// GetDecimal 读取 key 并转换为 Decimal，不经过 float64
+//
+// 支持的值：
+//  1. Decimal、json.Number、string、[]byte（数据库 DECIMAL 列通常以 []byte 返回）按字面量精确解析
+//  2. 整数类型、*big.Int 精确转换
+//  3. float32、float64、*big.Float 使用能还原该值的最短十进制表示，例如 100.1（尾随的 0 已经丢失）
+func GetDecimal(r *eorm.Record, key string) (Decimal, error) {
+	if !r.Has(key) {
+		return Decimal{}, fmt.Errorf("bignum: key '%s' not found", key)
+	}
+	d, err := toDecimal(r.Get(key))
+	if err != nil {
+		return Decimal{}, fmt.Errorf("bignum: '%s': %v", key, err)
+	}
+	return d, nil
+}
+
+// GetBigInt 读取 key 并转换为 *big.Int，值带有非零小数部分时返回错误
+func GetBigInt(r *eorm.Record, key string) (*big.Int, error) {
+	d, err := GetDecimal(r, key)
+	if err != nil {
+		return nil, err
+	}
+	n, err := toBigInt(d)
+	if err != nil {
+		return nil, fmt.Errorf("bignum: '%s': %v", key, err)
+	}
+	return n, nil
+}
+
+// GetBigFloat 读取 key 并转换为精度为 BigFloatPrec 的 *big.Float
+func GetBigFloat(r *eorm.Record, key string) (*big.Float, error) {
+	d, err := GetDecimal(r, key)
+	if err != nil {
+		return nil, err
+	}
+	return toBigFloat(d), nil
+}
+
+// GetDecimalByPath 按点分路径读取并转换为 Decimal，数字段按数组下标访问，例如 "orders.0.amount"
+func GetDecimalByPath(r *eorm.Record, path string) (Decimal, error) {
+	v, err := lookupPath(r, path)
+	if err != nil {
+		return Decimal{}, err
+	}
+	d, err := toDecimal(v)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("bignum: '%s': %v", path, err)
+	}
+	return d, nil
+}
+
+// GetBigIntByPath 按点分路径读取并转换为 *big.Int
+func GetBigIntByPath(r *eorm.Record, path string) (*big.Int, error) {
+	d, err := GetDecimalByPath(r, path)
+	if err != nil {
+		return nil, err
+	}
+	n, err := toBigInt(d)
+	if err != nil {
+		return nil, fmt.Errorf("bignum: '%s': %v", path, err)
+	}
+	return n, nil
+}
+
+// GetBigFloatByPath 按点分路径读取并转换为 *big.Float
+func GetBigFloatByPath(r *eorm.Record, path string) (*big.Float, error) {
+	d, err := GetDecimalByPath(r, path)
+	if err != nil {
+		return nil, err
+	}
+	return toBigFloat(d), nil
+}
+
+func toDecimal(v interface{}) (Decimal, error) {
+	switch val := v.(type) {
+	case nil:
+		return Decimal{}, fmt.Errorf("value is nil")
+	case Decimal:
+		return val, nil
+	case *Decimal:
+		if val == nil {
+			return Decimal{}, fmt.Errorf("value is nil")
+		}
+		return *val, nil
+	case json.Number:
+		return ParseDecimal(string(val))
+	case string:
+		return ParseDecimal(val)
+	case []byte:
+		return ParseDecimal(string(val))
+	case *big.Int:
+		return DecimalFromBigInt(val), nil
+	case *big.Float:
+		return ParseDecimal(val.Text('f', -1))
+	case float32:
+		return ParseDecimal(strconv.FormatFloat(float64(val), 'f', -1, 32))
+	case float64:
+		return ParseDecimal(strconv.FormatFloat(val, 'f', -1, 64))
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return DecimalFromBigInt(big.NewInt(rv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return DecimalFromBigInt(new(big.Int).SetUint64(rv.Uint())), nil
+	}
+	return Decimal{}, fmt.Errorf("cannot convert %T to decimal", v)
+}
+
+func toBigInt(d Decimal) (*big.Int, error) {
+	q, r := new(big.Int).QuoRem(d.int(), pow10(d.scale), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, fmt.Errorf("%s has a fractional part", d)
+	}
+	return q, nil
+}
+
+func toBigFloat(d Decimal) *big.Float {
+	f, _, _ := big.ParseFloat(d.String(), 10, BigFloatPrec, big.ToNearestEven)
+	return f
+}
+
+// JSONOptions 控制 FromJsonWithOptions 的解析方式
+type JSONOptions struct {
+	// ExactNumbers 为 true 时保留数字的原始字面量：能精确表示为 int64 的整数保存为 int64，
+	// 其他数字保存为 json.Number，ToJson 会原样写出
+	// 为 false 时与 Record.FromJson 一致，数字保存为 float64
+	ExactNumbers bool
+}
+
+// FromJsonWithOptions 解析 JSON 对象，键的顺序与原文一致
+// 嵌套对象保存为 *Record，元素全部是对象的数组保存为 []*Record，其他数组保存为 []interface{}
+//
+// 注意：json.Number 不是 float64，Record.GetFloat、GetInt 对它返回 0，请使用 GetDecimal、GetBigInt、GetBigFloat 读取
+func FromJsonWithOptions(data string, opts JSONOptions) (*eorm.Record, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("bignum: %v", err)
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("bignum: JSON must be an object")
+	}
+	p := jsonParser{dec: dec, opts: opts}
+	rec, err := p.object()
+	if err != nil {
+		return nil, fmt.Errorf("bignum: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("bignum: unexpected data after JSON object")
+	}
+	return rec, nil
+}
+
+// FromJsonExact 等同于 FromJsonWithOptions(data, JSONOptions{ExactNumbers: true})
+func FromJsonExact(data string) (*eorm.Record, error) {
+	return FromJsonWithOptions(data, JSONOptions{ExactNumbers: true})
+}
+
+type jsonParser struct {
+	dec  *json.Decoder
+	opts JSONOptions
+}
+
+// object 解析 '{' 之后的内容，子 Record 解析完成后再 Set，因为 Set 会按值保存嵌套 Record
+func (p *jsonParser) object() (*eorm.Record, error) {
+	rec := eorm.NewRecord()
+	for p.dec.More() {
+		tok, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		rec.Set(key, v)
+	}
+	_, err := p.dec.Token() // '}'
+	return rec, err
+}
+
+func (p *jsonParser) array() (interface{}, error) {
+	items := []interface{}{}
+	allRecords := true
+	for p.dec.More() {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(*eorm.Record); !ok {
+			allRecords = false
+		}
+		items = append(items, v)
+	}
+	if _, err := p.dec.Token(); err != nil { // ']'
+		return nil, err
+	}
+	if allRecords && len(items) > 0 {
+		recs := make([]*eorm.Record, len(items))
+		for i, item := range items {
+			recs[i] = item.(*eorm.Record)
+		}
+		return recs, nil
+	}
+	return items, nil
+}
+
+func (p *jsonParser) value() (interface{}, error) {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return p.object()
+		}
+		return p.array()
+	case json.Number:
+		return p.number(t)
+	}
+	return tok, nil
+}
+
+func (p *jsonParser) number(n json.Number) (interface{}, error) {
+	if !p.opts.ExactNumbers {
+		return n.Float64()
+	}
+	// 只有字面量能原样还原时才使用 int64，例如 "1e3"、"-0" 仍保存为 json.Number
+	if i, err := n.Int64(); err == nil && strconv.FormatInt(i, 10) == string(n) {
+		return i, nil
+	}
+	return n, nil
+}
+
+// PrepareExact 返回 r 的浅拷贝，其中 *big.Int、*big.Float、json.Number 转换为 Decimal
+// Decimal 实现了 driver.Valuer，以字符串形式传给驱动，InsertRecord、UpdateRecord 写入 DECIMAL 列时不会经过 float64
+func PrepareExact(r *eorm.Record) *eorm.Record {
+	out := eorm.NewRecord()
+	for _, key := range r.Keys() {
+		v := r.Get(key)
+		switch v.(type) {
+		case *big.Int, *big.Float, json.Number:
+			if d, err := toDecimal(v); err == nil {
+				v = d
+			}
+		}
+		out.Set(key, v)
+	}
+	return out
+}
+
+// lookupPath 按点分路径查找值，Record 键名大小写不敏感，数字段按数组下标访问
+func lookupPath(r *eorm.Record, path string) (interface{}, error) {
+	if path == "" {
+		return nil, fmt.Errorf("bignum: path cannot be empty")
+	}
+	var cur interface{} = r
+	walked := ""
+	for _, seg := range strings.Split(path, ".") {
+		if rec := asRecord(cur); rec != nil {
+			if !rec.Has(seg) {
+				return nil, fmt.Errorf("bignum: path '%s' not found at part '%s'", path, seg)
+			}
+			cur = rec.Get(seg)
+			walked = joinPath(walked, seg)
+			continue
+		}
+		rv := reflect.ValueOf(cur)
+		if cur != nil && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= rv.Len() {
+				return nil, fmt.Errorf("bignum: path '%s' index '%s' out of range at '%s'", path, seg, walked)
+			}
+			cur = rv.Index(i).Interface()
+			walked = joinPath(walked, seg)
+			continue
+		}
+		return nil, fmt.Errorf("bignum: path '%s' cannot descend into %T at '%s'", path, cur, walked)
+	}
+	return cur, nil
+}
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例28：时间处理
+// 演示自定义解析格式、时区、时间戳单位识别、GetDuration，以及单个 Record 的 ToJson 时间格式
+func main() {
+	fmt.Println("========== 时间处理示例 ==========")
+
+	record := eorm.NewRecord().
+		Set("created_at", "2024-01-01").
+		Set("updated_at", "2024-05-01 10:30:00").
+		Set("login_sec", int64(1714530600)).
+		Set("login_ms", int64(1714530600123)).
+		Set("login_float", 1714530600.5).
+		Set("expires", "Wed, 01 May 2024 10:30:00 GMT").
+		Set("birthday", "01/05/1990").
+		Set("ttl", "30s").
+		Set("timeout", 3600).
+		Set("retry", "1.5")
+
+	// 1. Record.GetTime 的局限
+	fmt.Println("\n1. Record.GetTime 的局限")
+	fmt.Printf("   login_ms: %s\n", record.GetTime("login_ms"))
+	fmt.Printf("   login_float: %s\n", record.GetTime("login_float"))
+	fmt.Printf("   updated_at: %s（不带时区，按 UTC 解析）\n", record.GetTime("updated_at"))
+
+	// 2. 时间戳单位自动识别
+	fmt.Println("\n2. 时间戳单位自动识别")
+	tr := WithTimeOptions(record, TimeOptions{})
+	for _, key := range []string{"login_sec", "login_ms", "login_float"} {
+		fmt.Printf("   %s: %s\n", key, tr.GetTime(key).Format(time.RFC3339Nano))
+	}
+	fmt.Printf("   expires (RFC1123): %s\n", tr.GetTime("expires").Format(time.RFC3339))
+
+	// 3. 指定格式
+	fmt.Println("\n3. 指定格式")
+	if _, err := GetTimeLayout(record, "birthday"); err != nil {
+		fmt.Printf("   默认格式 (预期): %v\n", err)
+	}
+	birthday, _ := GetTimeLayout(record, "birthday", "02/01/2006")
+	fmt.Printf("   birthday (02/01/2006): %s\n", birthday.Format("2006-01-02"))
+
+	// 4. 时区
+	fmt.Println("\n4. 时区")
+	shanghai := time.FixedZone("Asia/Shanghai", 8*3600)
+	local := WithTimeOptions(record, TimeOptions{Location: shanghai})
+	fmt.Printf("   updated_at (Asia/Shanghai): %s\n", local.GetTime("updated_at").Format(time.RFC3339))
+	fmt.Printf("   login_sec (Asia/Shanghai): %s\n", local.GetTime("login_sec").Format(time.RFC3339))
+	utc, _ := local.GetTimeIn("updated_at", time.UTC)
+	fmt.Printf("   GetTimeIn(UTC): %s\n", utc.Format(time.RFC3339))
+
+	// 5. GetDuration
+	fmt.Println("\n5. GetDuration")
+	for _, key := range []string{"ttl", "timeout", "retry"} {
+		d, _ := GetDuration(record, key)
+		fmt.Printf("   %s: %v\n", key, d)
+	}
+	ms := WithTimeOptions(record, TimeOptions{DurationUnit: time.Millisecond})
+	timeout, _ := ms.GetDuration("timeout")
+	fmt.Printf("   timeout (毫秒): %v\n", timeout)
+
+	// 6. ToJson 的时间格式
+	fmt.Println("\n6. ToJson 的时间格式")
+	event := eorm.NewRecord().
+		Set("name", "deploy").
+		Set("at", time.Date(2024, 5, 1, 2, 30, 0, 123456789, time.UTC)).
+		Set("meta", eorm.NewRecord().Set("finished_at", time.Date(2024, 5, 1, 2, 45, 0, 0, time.UTC)))
+	fmt.Printf("   Record.ToJson: %s\n", event.ToJson())
+	fmt.Printf("   默认格式: %s\n", WithTimeOptions(event, TimeOptions{}).ToJson())
+	custom := WithTimeOptions(event, TimeOptions{Format: "2006-01-02 15:04:05", Location: shanghai})
+	fmt.Printf("   自定义格式和时区: %v\n", custom)
+
+	// 7. 错误处理
+	fmt.Println("\n7. 错误处理")
+	if _, err := tr.GetTimeE("missing"); err != nil {
+		fmt.Printf("   键不存在 (预期): %v\n", err)
+	}
+	if _, err := tr.GetTimeE("ttl"); err != nil {
+		fmt.Printf("   无法解析 (预期): %v\n", err)
+	}
+	if _, err := GetDuration(record, "created_at"); err != nil {
+		fmt.Printf("   非法时长 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
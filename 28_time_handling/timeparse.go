@@ -0,0 +1,329 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// EpochUnit 指定整数、浮点数时间戳的单位
+type EpochUnit int
+
+const (
+	EpochAuto    EpochUnit = iota // 按数值大小自动识别秒、毫秒、微秒、纳秒
+	EpochSeconds                  // 秒，浮点数的小数部分为秒以下的部分
+	EpochMillis                   // 毫秒
+	EpochMicros                   // 微秒
+	EpochNanos                    // 纳秒
+)
+
+// DefaultLayouts 是 TimeOptions.Layouts 为空时尝试的格式，按顺序匹配
+var DefaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+// TimeOptions 控制时间的解析和输出
+type TimeOptions struct {
+	// Layouts 是解析字符串时依次尝试的格式，为空时使用 DefaultLayouts
+	Layouts []string
+	// Location 用于解析不带时区的字符串（如 "2024-01-01"），时间戳和 ToJson 的输出也转换到该时区
+	// 为 nil 时使用 UTC，与 Record.GetTime 一致
+	Location *time.Location
+	// Epoch 是整数、浮点数以及纯数字字符串的时间戳单位，默认按数值大小自动识别
+	Epoch EpochUnit
+	// DurationUnit 是 GetDuration 读取数值时的单位，为 0 时使用秒，因此 3600 表示一小时
+	DurationUnit time.Duration
+	// Format 是 ToJson 输出 time.Time 的格式，为空时使用 time.RFC3339
+	Format string
+}
+
+func (o TimeOptions) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+// ParseTime 按 opts 将值转换为 time.Time
+//
+// 转换规则：
+//  1. time.Time、*time.Time 转换到 opts.Location
+//  2. 整数、浮点数作为时间戳，单位由 opts.Epoch 决定
+//  3. 字符串依次尝试 opts.Layouts，不带时区的格式按 opts.Location 解析；都不匹配且为纯数字时作为时间戳，
+//     因此 "20240101" 这类字符串需要指定 "20060102" 格式
+func ParseTime(v interface{}, opts TimeOptions) (time.Time, error) {
+	loc := opts.location()
+	switch val := v.(type) {
+	case nil:
+		return time.Time{}, fmt.Errorf("time: value is nil")
+	case time.Time:
+		return val.In(loc), nil
+	case *time.Time:
+		if val == nil {
+			return time.Time{}, fmt.Errorf("time: value is nil")
+		}
+		return val.In(loc), nil
+	case string:
+		return parseTimeString(val, opts)
+	case []byte:
+		return parseTimeString(string(val), opts)
+	}
+	f, ok := numberOf(v)
+	if !ok {
+		return time.Time{}, fmt.Errorf("time: cannot convert %T to time.Time", v)
+	}
+	return fromEpoch(f, opts.Epoch).In(loc), nil
+}
+
+func parseTimeString(s string, opts TimeOptions) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("time: empty string")
+	}
+	layouts := opts.Layouts
+	if len(layouts) == 0 {
+		layouts = DefaultLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, opts.location()); err == nil {
+			return t, nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && isNumeric(s) {
+		return fromEpoch(f, opts.Epoch).In(opts.location()), nil
+	}
+	return time.Time{}, fmt.Errorf("time: cannot parse %q (tried %d layouts)", s, len(layouts))
+}
+
+// isNumeric 判断字符串是否只包含数字、可选的负号和最多一个小数点
+func isNumeric(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return s != "" && strings.Trim(s, "0123456789.") == "" && strings.Count(s, ".") <= 1
+}
+
+func numberOf(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// detectEpoch 按数值大小识别单位：秒级时间戳在 5138 年之前小于 1e11，毫秒、微秒依次类推
+func detectEpoch(f float64) EpochUnit {
+	switch abs := math.Abs(f); {
+	case abs < 1e11:
+		return EpochSeconds
+	case abs < 1e14:
+		return EpochMillis
+	case abs < 1e17:
+		return EpochMicros
+	}
+	return EpochNanos
+}
+
+func fromEpoch(f float64, unit EpochUnit) time.Time {
+	if unit == EpochAuto {
+		unit = detectEpoch(f)
+	}
+	switch unit {
+	case EpochMillis:
+		return time.UnixMilli(int64(f))
+	case EpochMicros:
+		return time.UnixMicro(int64(f))
+	case EpochNanos:
+		return time.Unix(0, int64(f))
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9)))
+}
+
+// ParseDuration 按 opts 将值转换为 time.Duration
+// 字符串按 time.ParseDuration 解析（如 "30s"、"1h30m"），数值和纯数字字符串乘以 opts.DurationUnit
+func ParseDuration(v interface{}, opts TimeOptions) (time.Duration, error) {
+	unit := opts.DurationUnit
+	if unit == 0 {
+		unit = time.Second
+	}
+	switch val := v.(type) {
+	case nil:
+		return 0, fmt.Errorf("time: value is nil")
+	case time.Duration:
+		return val, nil
+	case string:
+		s := strings.TrimSpace(val)
+		if isNumeric(s) {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, fmt.Errorf("time: invalid duration %q", val)
+			}
+			return time.Duration(f * float64(unit)), nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("time: invalid duration %q", val)
+		}
+		return d, nil
+	}
+	f, ok := numberOf(v)
+	if !ok {
+		return 0, fmt.Errorf("time: cannot convert %T to time.Duration", v)
+	}
+	return time.Duration(f * float64(unit)), nil
+}
+
+// GetTimeLayout 读取 key 并按指定格式解析，不带时区的格式按 UTC 解析
+// 未指定 layouts 时使用 DefaultLayouts；需要指定时区时使用 TimeRecord
+func GetTimeLayout(r *eorm.Record, key string, layouts ...string) (time.Time, error) {
+	return WithTimeOptions(r, TimeOptions{}).GetTimeLayout(key, layouts...)
+}
+
+// GetDuration 读取 key 并转换为 time.Duration，数值按秒计算
+func GetDuration(r *eorm.Record, key string) (time.Duration, error) {
+	return WithTimeOptions(r, TimeOptions{}).GetDuration(key)
+}
+
+// TimeRecord 将 Record 与 TimeOptions 绑定，为单个 Record 指定解析格式、时区和输出格式
+// Record 的其他方法仍然可以直接调用
+type TimeRecord struct {
+	*eorm.Record
+	opts TimeOptions
+}
+
+// WithTimeOptions 将 r 与 opts 绑定
+func WithTimeOptions(r *eorm.Record, opts TimeOptions) TimeRecord {
+	return TimeRecord{Record: r, opts: opts}
+}
+
+// Options 返回绑定的 TimeOptions
+func (t TimeRecord) Options() TimeOptions {
+	return t.opts
+}
+
+// GetTime 按绑定的选项读取时间，key 不存在或解析失败时返回零值，与 Record.GetTime 一致
+func (t TimeRecord) GetTime(key string) time.Time {
+	tm, _ := t.GetTimeE(key)
+	return tm
+}
+
+// GetTimeE 与 GetTime 相同，解析失败时返回错误
+func (t TimeRecord) GetTimeE(key string) (time.Time, error) {
+	return t.GetTimeLayout(key, t.opts.Layouts...)
+}
+
+// GetTimeLayout 读取 key 并按 layouts 解析，layouts 为空时使用绑定的 Layouts 或 DefaultLayouts
+func (t TimeRecord) GetTimeLayout(key string, layouts ...string) (time.Time, error) {
+	if !t.Has(key) {
+		return time.Time{}, fmt.Errorf("time: key '%s' not found", key)
+	}
+	opts := t.opts
+	if len(layouts) > 0 {
+		opts.Layouts = layouts
+	}
+	tm, err := ParseTime(t.Get(key), opts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time: '%s': %v", key, strings.TrimPrefix(err.Error(), "time: "))
+	}
+	return tm, nil
+}
+
+// GetTimeIn 读取 key 并按 loc 解析，不修改绑定的时区
+func (t TimeRecord) GetTimeIn(key string, loc *time.Location) (time.Time, error) {
+	opts := t.opts
+	opts.Location = loc
+	return WithTimeOptions(t.Record, opts).GetTimeE(key)
+}
+
+// GetDuration 读取 key 并转换为 time.Duration
+func (t TimeRecord) GetDuration(key string) (time.Duration, error) {
+	if !t.Has(key) {
+		return 0, fmt.Errorf("time: key '%s' not found", key)
+	}
+	d, err := ParseDuration(t.Get(key), t.opts)
+	if err != nil {
+		return 0, fmt.Errorf("time: '%s': %v", key, strings.TrimPrefix(err.Error(), "time: "))
+	}
+	return d, nil
+}
+
+// ToJson 与 Record.ToJson 相同，time.Time 按 Format 和 Location 输出，嵌套 Record 和数组中的时间同样处理
+func (t TimeRecord) ToJson() string {
+	return formatTimes(t.Record, t.opts).ToJson()
+}
+
+// String 实现 Stringer 接口，使 fmt.Printf("%v") 也使用绑定的格式
+func (t TimeRecord) String() string {
+	return t.ToJson()
+}
+
+// formatTimes 返回新的 Record，time.Time 替换为格式化后的字符串
+func formatTimes(r *eorm.Record, opts TimeOptions) *eorm.Record {
+	out := eorm.NewRecord()
+	for _, key := range r.Keys() {
+		out.Set(key, formatValue(r.Get(key), opts))
+	}
+	return out
+}
+
+func formatValue(v interface{}, opts TimeOptions) interface{} {
+	layout := opts.Format
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	switch val := v.(type) {
+	case time.Time:
+		return val.In(opts.location()).Format(layout)
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.In(opts.location()).Format(layout)
+	case *eorm.Record:
+		return formatTimes(val, opts)
+	case []*eorm.Record:
+		out := make([]*eorm.Record, len(val))
+		for i, rec := range val {
+			out[i] = formatTimes(rec, opts)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = formatValue(item, opts)
+		}
+		return out
+	case []time.Time:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = formatValue(item, opts)
+		}
+		return out
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return formatTimes(rec, opts)
+	}
+	return v
+}
+
+var recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
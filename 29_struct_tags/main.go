@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// Timestamps 是多个模型共用的审计字段
+type Timestamps struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at,omitempty"`
+}
+
+// User 是数据库模型，db 标签与表的列名一致，json 标签用于 API 输出
+type User struct {
+	ID       int64  `db:"id" json:"id"`
+	UserName string `db:"user_name" json:"userName"`
+	Email    string `db:"email,omitempty" json:"email,omitempty"`
+	Password string `db:"password_hash" json:"-"`
+	Timestamps
+}
+
+// ServiceConfig 使用 mapstructure 标签，没有标签的字段按命名策略生成列名
+type ServiceConfig struct {
+	HTTPPort   int           `mapstructure:"http_port"`
+	LogLevel   string        `mapstructure:"log_level,omitempty"`
+	MaxRetries int           // 没有标签
+	Timeout    time.Duration `mapstructure:"timeout"`
+	Database   DBConfig      `mapstructure:",inline"`
+	Internal   string        `mapstructure:"-"`
+}
+
+type DBConfig struct {
+	DSN     string `mapstructure:"dsn"`
+	MaxConn int    `mapstructure:"max_conn"`
+}
+
+// Article 使用自定义标签 rec，没有标签的字段使用 snake_case
+type Article struct {
+	ArticleID int64    `rec:"id"`
+	Title     string   `rec:"title"`
+	AuthorID  int64    // author_id
+	ViewCount int      // view_count
+	Tags      []string `rec:",omitempty"`
+	Author    *Author  `rec:"author,omitempty"`
+}
+
+type Author struct {
+	Name     string
+	HomePage string
+}
+
+// 示例29：结构体标签和命名策略
+// 演示按 db、mapstructure 或自定义标签映射，标签优先级、命名策略以及 omitempty、-、inline 在两个方向上的行为
+func main() {
+	fmt.Println("========== 结构体标签示例 ==========")
+
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	user := User{ID: 1, UserName: "张三", Password: "bcrypt$...", Timestamps: Timestamps{CreatedAt: created}}
+
+	// 1. eorm 默认规则：column > db > json
+	fmt.Println("\n1. 默认规则 (column > db > json)")
+	record, _ := FromStruct(user)
+	fmt.Printf("   %s\n", record.ToJson())
+	fmt.Println("   Timestamps 是匿名嵌入字段，展开到上一层；email 为空被 omitempty 跳过")
+	fmt.Println(`   _, err := eorm.InsertRecord("users", record)`)
+
+	// 2. 指定标签优先级：API 输出使用 json 标签
+	fmt.Println("\n2. 标签优先级 (json > db)")
+	api := NewMapper(MapperOptions{TagNames: []string{"json", "db"}})
+	apiRecord, _ := api.FromStruct(user)
+	fmt.Printf("   %s\n", apiRecord.ToJson())
+	fmt.Println("   password_hash 的 json 标签为 \"-\"，不会输出；Timestamps 中没有 json 标签的字段回退到 db 标签")
+
+	// 3. ToStruct 使用同样的规则
+	fmt.Println("\n3. ToStruct")
+	row := eorm.NewRecord().FromJson(`{"id": 2, "USER_NAME": "李四", "email": null, "password_hash": "x", "created_at": "2024-05-02T08:00:00Z"}`)
+	loaded := User{Email: "keep@example.com"}
+	if err := ToStruct(row, &loaded); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	fmt.Printf("   ID=%d UserName=%s Email=%s CreatedAt=%s\n", loaded.ID, loaded.UserName, loaded.Email, loaded.CreatedAt.Format(time.RFC3339))
+	fmt.Println("   列名不区分大小写；email 为 null 且有 omitempty，保持原值")
+
+	// 4. mapstructure 标签和 inline
+	fmt.Println("\n4. mapstructure 标签和 inline")
+	cfgMapper := NewMapper(MapperOptions{TagNames: []string{"mapstructure"}, Naming: SnakeCase})
+	cfg := ServiceConfig{HTTPPort: 8080, MaxRetries: 3, Timeout: 5 * time.Second, Database: DBConfig{DSN: "mysql://localhost/app", MaxConn: 20}, Internal: "secret"}
+	cfgRecord, _ := cfgMapper.FromStruct(cfg)
+	fmt.Printf("   %s\n", cfgRecord.ToJson())
+	var cfgBack ServiceConfig
+	cfgRecord.Set("max_conn", "50").Set("internal", "ignored")
+	_ = cfgMapper.ToStruct(cfgRecord, &cfgBack)
+	fmt.Printf("   往返: HTTPPort=%d MaxRetries=%d Timeout=%v DSN=%s MaxConn=%d Internal=%q\n",
+		cfgBack.HTTPPort, cfgBack.MaxRetries, cfgBack.Timeout, cfgBack.Database.DSN, cfgBack.Database.MaxConn, cfgBack.Internal)
+
+	// 5. 自定义标签和命名策略
+	fmt.Println("\n5. 自定义标签和命名策略")
+	article := Article{ArticleID: 7, Title: "Go 泛型", AuthorID: 1, ViewCount: 128, Author: &Author{Name: "王五", HomePage: "https://example.com"}}
+	for _, c := range []struct {
+		name   string
+		naming NamingStrategy
+	}{{"SnakeCase", SnakeCase}, {"CamelCase", CamelCase}, {"LowerCase", LowerCase}} {
+		m := NewMapper(MapperOptions{TagNames: []string{"rec"}, Naming: c.naming})
+		rec, _ := m.FromStruct(article)
+		fmt.Printf("   %-9s %s\n", c.name+":", rec.ToJson())
+	}
+
+	// 6. 嵌套结构体往返
+	fmt.Println("\n6. 嵌套结构体往返")
+	snake := NewMapper(MapperOptions{TagNames: []string{"rec"}, Naming: SnakeCase})
+	articleRecord, _ := snake.FromStruct(article)
+	var articleBack Article
+	if err := snake.ToStruct(articleRecord, &articleBack); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	} else {
+		fmt.Printf("   ✅ ArticleID=%d Title=%s AuthorID=%d ViewCount=%d Author=%+v\n",
+			articleBack.ArticleID, articleBack.Title, articleBack.AuthorID, articleBack.ViewCount, *articleBack.Author)
+	}
+
+	// 7. 全部字段 omitempty
+	fmt.Println("\n7. 全部字段 omitempty")
+	partial := NewMapper(MapperOptions{TagNames: []string{"db"}, OmitEmpty: true})
+	patch, _ := partial.FromStruct(User{ID: 1, Email: "new@example.com"})
+	fmt.Printf("   只包含非零值: %s\n", patch.ToJson())
+
+	// 8. 错误处理
+	fmt.Println("\n8. 错误处理")
+	bad := eorm.NewRecord().Set("id", "abc").Set("author", "王五")
+	if err := snake.ToStruct(bad, &Article{}); err != nil {
+		fmt.Printf("   类型不匹配 (预期): %v\n", err)
+	}
+	if _, err := FromStruct(42); err != nil {
+		fmt.Printf("   非结构体 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
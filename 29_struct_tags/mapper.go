@@ -0,0 +1,454 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// NamingStrategy 在字段没有标签或标签只有选项（如 json:",omitempty"）时，由字段名生成列名
+type NamingStrategy func(field string) string
+
+var (
+	// LowerCase 将字段名转为小写，UserName -> username，与 eorm 一致
+	LowerCase NamingStrategy = strings.ToLower
+	// SnakeCase UserName -> user_name，UserID -> user_id，HTTPServer -> http_server
+	SnakeCase NamingStrategy = func(field string) string {
+		return strings.ToLower(strings.Join(splitWords(field), "_"))
+	}
+	// CamelCase UserName -> userName，UserID -> userId
+	CamelCase NamingStrategy = func(field string) string {
+		words := splitWords(field)
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			words[i] = w
+		}
+		return strings.Join(words, "")
+	}
+	// FieldName 直接使用字段名
+	FieldName NamingStrategy = func(field string) string { return field }
+)
+
+// splitWords 按大小写边界拆分 Go 标识符，连续的大写字母视为一个缩写
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case cur == '_':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case unicode.IsLower(prev) && unicode.IsUpper(cur),
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && unicode.IsLower(next),
+			unicode.IsLetter(prev) && unicode.IsDigit(cur) && !unicode.IsUpper(prev):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// MapperOptions 控制结构体与 Record 之间的映射
+type MapperOptions struct {
+	// TagNames 是按优先级读取的标签名，为空时使用 column、db、json，与 eorm 一致
+	TagNames []string
+	// Naming 是没有列名时使用的命名策略，为 nil 时使用 LowerCase
+	Naming NamingStrategy
+	// OmitEmpty 为 true 时所有字段都按 omitempty 处理
+	OmitEmpty bool
+}
+
+// Mapper 按 MapperOptions 在结构体与 Record 之间转换，字段映射按结构体类型缓存，可以并发使用
+//
+// 标签规则（两个方向一致）：
+//  1. 按 TagNames 顺序使用第一个存在的标签，列名为空时使用命名策略
+//  2. "-" 忽略字段
+//  3. omitempty：FromStruct 跳过零值；ToStruct 时值为 nil 的键保持字段不变
+//  4. inline：嵌套结构体的字段展开到上一层；没有列名的匿名嵌入结构体同样展开，与 encoding/json 一致
+//  5. 多个字段映射到同一列时，层级较浅的字段优先，同一层级先声明的优先
+type Mapper struct {
+	opts  MapperOptions
+	plans sync.Map // reflect.Type -> []fieldPlan
+}
+
+// fieldPlan 描述一个映射到列的字段，index 是从外层结构体开始的字段下标路径
+type fieldPlan struct {
+	column    string
+	index     []int
+	omitEmpty bool
+}
+
+// DefaultMapper 与 eorm 的 FromStruct、ToStruct 规则一致
+var DefaultMapper = NewMapper(MapperOptions{})
+
+// NewMapper 创建 Mapper
+func NewMapper(opts MapperOptions) *Mapper {
+	if len(opts.TagNames) == 0 {
+		opts.TagNames = []string{"column", "db", "json"}
+	}
+	if opts.Naming == nil {
+		opts.Naming = LowerCase
+	}
+	return &Mapper{opts: opts}
+}
+
+// parseTag 按优先级读取标签，返回列名和选项
+func (m *Mapper) parseTag(f reflect.StructField) (name string, opts map[string]bool) {
+	for _, tagName := range m.opts.TagNames {
+		tag, ok := f.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		opts = make(map[string]bool)
+		for _, opt := range parts[1:] {
+			opts[strings.TrimSpace(opt)] = true
+		}
+		return strings.TrimSpace(parts[0]), opts
+	}
+	return "", map[string]bool{}
+}
+
+func (m *Mapper) plan(t reflect.Type) []fieldPlan {
+	if cached, ok := m.plans.Load(t); ok {
+		return cached.([]fieldPlan)
+	}
+	var fields []fieldPlan
+	depth := make(map[string]int)
+	var walk func(t reflect.Type, prefix []int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, prefix []int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts := m.parseTag(f)
+			if name == "-" && len(opts) == 0 {
+				continue
+			}
+			index := append(append([]int{}, prefix...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			// 未导出的字段只有以值嵌入时才能展开，其他情况 ToStruct 无法赋值，与 encoding/json 一致跳过
+			if !f.IsExported() && (!f.Anonymous || f.Type.Kind() == reflect.Ptr) {
+				continue
+			}
+			inline := opts["inline"] || (f.Anonymous && name == "")
+			if inline && ft.Kind() == reflect.Struct && ft != timeType {
+				walk(ft, index, visited)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = m.opts.Naming(f.Name)
+			}
+			key := strings.ToLower(name)
+			if d, exists := depth[key]; exists && d <= len(index) {
+				continue
+			} else if exists {
+				// 层级更浅的字段覆盖已有的映射
+				for j := range fields {
+					if strings.EqualFold(fields[j].column, name) {
+						fields = append(fields[:j], fields[j+1:]...)
+						break
+					}
+				}
+			}
+			depth[key] = len(index)
+			fields = append(fields, fieldPlan{column: name, index: index, omitEmpty: opts["omitempty"] || m.opts.OmitEmpty})
+		}
+	}
+	walk(t, nil, map[reflect.Type]bool{})
+	m.plans.Store(t, fields)
+	return fields
+}
+
+// FromStruct 从结构体创建 Record，src 可以是结构体或结构体指针
+// 嵌套结构体转换为 *Record，结构体切片转换为 []*Record，time.Time 原样保存
+func (m *Mapper) FromStruct(src interface{}) (*eorm.Record, error) {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("mapper: src is nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mapper: src must be a struct or pointer to struct, got %T", src)
+	}
+	return m.fromStruct(v), nil
+}
+
+func (m *Mapper) fromStruct(v reflect.Value) *eorm.Record {
+	rec := eorm.NewRecord()
+	for _, f := range m.plan(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			// 内嵌的指针为 nil，其中的字段都不存在
+			continue
+		}
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		rec.Set(f.column, m.fromValue(fv))
+	}
+	return rec
+}
+
+func (m *Mapper) fromValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return m.fromValue(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType || v.Type() == recordType {
+			return v.Interface()
+		}
+		return m.fromStruct(v)
+	case reflect.Slice, reflect.Array:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct || elem == timeType || elem == recordType {
+			return v.Interface()
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		recs := make([]*eorm.Record, v.Len())
+		for i := range recs {
+			recs[i], _ = m.fromValue(v.Index(i)).(*eorm.Record)
+		}
+		return recs
+	}
+	return v.Interface()
+}
+
+// fieldByIndex 与 reflect.Value.FieldByIndex 相同，路径上的指针为 nil 时返回 false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc 与 fieldByIndex 相同，路径上为 nil 的指针会被分配
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// ToStruct 将 Record 转换为结构体，dest 必须是结构体指针
+// 列名匹配不区分大小写；Record 中不存在的列保持字段原值
+func (m *Mapper) ToStruct(r *eorm.Record, dest interface{}) error {
+	if r == nil {
+		return fmt.Errorf("mapper: record is nil")
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("mapper: dest must be a non-nil pointer to a struct, got %T", dest)
+	}
+	return m.toStruct(r, v.Elem(), "")
+}
+
+func (m *Mapper) toStruct(r *eorm.Record, v reflect.Value, path string) error {
+	for _, f := range m.plan(v.Type()) {
+		if !r.Has(f.column) {
+			continue
+		}
+		value := r.Get(f.column)
+		if value == nil && f.omitEmpty {
+			continue
+		}
+		fieldPath := joinPath(path, f.column)
+		if err := m.assign(fieldByIndexAlloc(v, f.index), value, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assign 将 value 转换后写入 dst
+func (m *Mapper) assign(dst reflect.Value, value interface{}, path string) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) && dst.Kind() != reflect.Struct {
+		dst.Set(src)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := m.assign(elem.Elem(), value, path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Struct:
+		if dst.Type() == timeType {
+			t, err := eorm.Convert.ToTimeWithError(value)
+			if err != nil {
+				return fmt.Errorf("mapper: '%s': %v", path, err)
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		if src.Type() == dst.Type() {
+			dst.Set(src)
+			return nil
+		}
+		rec := asRecord(value)
+		if rec == nil {
+			return fmt.Errorf("mapper: '%s': cannot convert %T to %s", path, value, dst.Type())
+		}
+		return m.toStruct(rec, dst, path)
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return fmt.Errorf("mapper: '%s': cannot convert %T to %s", path, value, dst.Type())
+		}
+		out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := m.assign(out.Index(i), src.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Interface:
+		if src.Type().Implements(dst.Type()) {
+			dst.Set(src)
+			return nil
+		}
+	case reflect.String:
+		s, err := eorm.Convert.ToStringWithError(value)
+		if err != nil {
+			return fmt.Errorf("mapper: '%s': %v", path, err)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := eorm.Convert.ToBoolWithError(value)
+		if err != nil {
+			return fmt.Errorf("mapper: '%s': %v", path, err)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := eorm.Convert.ToInt64WithError(value)
+		if err != nil {
+			return fmt.Errorf("mapper: '%s': %v", path, err)
+		}
+		if dst.OverflowInt(n) {
+			return fmt.Errorf("mapper: '%s': value %d overflows %s", path, n, dst.Type())
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := eorm.Convert.ToUint64WithError(value)
+		if err != nil {
+			return fmt.Errorf("mapper: '%s': %v", path, err)
+		}
+		if dst.OverflowUint(n) {
+			return fmt.Errorf("mapper: '%s': value %d overflows %s", path, n, dst.Type())
+		}
+		dst.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := eorm.Convert.ToFloat64WithError(value)
+		if err != nil {
+			return fmt.Errorf("mapper: '%s': %v", path, err)
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	if src.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+	return fmt.Errorf("mapper: '%s': cannot convert %T to %s", path, value, dst.Type())
+}
+
+// FromStruct 使用 DefaultMapper 从结构体创建 Record
+func FromStruct(src interface{}) (*eorm.Record, error) {
+	return DefaultMapper.FromStruct(src)
+}
+
+// ToStruct 使用 DefaultMapper 将 Record 转换为结构体
+func ToStruct(r *eorm.Record, dest interface{}) error {
+	return DefaultMapper.ToStruct(r, dest)
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+type Address struct {
+	City    string `json:"city" validate:"required"`
+	ZipCode string `json:"zip_code"`
+}
+
+type Customer struct {
+	ID      int64    `json:"id" validate:"required"`
+	Name    string   `json:"name" validate:"required"`
+	Address *Address `json:"address"`
+}
+
+type Item struct {
+	SKU   string  `json:"sku" validate:"required"`
+	Qty   int8    `json:"qty"`
+	Price float32 `json:"price"`
+}
+
+type Order struct {
+	OrderID   int64     `json:"order_id" validate:"required"`
+	Status    string    `json:"status" validate:"required"`
+	Paid      bool      `json:"paid"`
+	Amount    float64   `json:"amount"`
+	Points    uint      `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+	Customer  Customer  `json:"customer"`
+	Items     []Item    `json:"items"`
+}
+
+// audit 是未导出的类型，Note 以指针嵌入，字段为 nil 时无法分配
+type audit struct {
+	UpdatedBy string `json:"updated_by"`
+}
+
+type Note struct {
+	*audit
+	Title string `json:"title"`
+}
+
+// 示例30：严格的 ToStruct
+// 演示报告未知列、缺少必填字段、类型不匹配和丢失信息的转换，一次返回全部问题及其在结构体中的路径
+func main() {
+	fmt.Println("========== 严格 ToStruct 示例 ==========")
+
+	valid := eorm.NewRecord().FromJson(`{
+		"order_id": 1001,
+		"status": "paid",
+		"paid": true,
+		"amount": 100.10,
+		"points": 20,
+		"created_at": "2024-05-01T10:30:00+08:00",
+		"customer": {"id": 1, "name": "张三", "address": {"city": "上海", "zip_code": "200000"}},
+		"items": [{"sku": "SKU-1", "qty": 2, "price": 99.5}]
+	}`)
+
+	// 1. 合法数据
+	fmt.Println("\n1. 合法数据")
+	var order Order
+	if err := ToStructStrict(valid, &order); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	} else {
+		fmt.Printf("   ✅ OrderID=%d Customer=%s City=%s Items=%d\n", order.OrderID, order.Customer.Name, order.Customer.Address.City, len(order.Items))
+	}
+
+	invalid := eorm.NewRecord().FromJson(`{
+		"order_id": 1002.5,
+		"paid": "yes",
+		"amount": "abc",
+		"points": -5,
+		"created_at": "tomorrow",
+		"coupon": "SPRING",
+		"customer": {"id": 2, "nickname": "小李", "address": {"zip_code": 100000}},
+		"items": [
+			{"sku": "SKU-1", "qty": 300, "price": 16777217},
+			{"qty": "2", "color": "red"}
+		]
+	}`)
+
+	// 2. eorm.ToStruct 的行为
+	fmt.Println("\n2. eorm.ToStruct 的行为")
+	var loose Order
+	err := eorm.ToStruct(invalid, &loose)
+	fmt.Printf("   err=%v OrderID=%d Points=%d Qty=%d\n", err, loose.OrderID, loose.Points, firstQty(loose.Items))
+
+	// 3. ToStructStrict 一次返回全部问题
+	fmt.Println("\n3. ToStructStrict")
+	var strict Order
+	err = ToStructStrict(invalid, &strict)
+	var problems FieldErrors
+	if errors.As(err, &problems) {
+		for _, p := range problems {
+			fmt.Printf("   %-8s %-22s %s\n", p.Kind, p.Path, p.Message)
+		}
+	}
+
+	// 4. 按类别筛选
+	fmt.Println("\n4. 按类别筛选")
+	for _, kind := range []ProblemKind{ProblemUnknown, ProblemRequired, ProblemType, ProblemLossy} {
+		fmt.Printf("   %s: %d\n", kind, len(problems.ByKind(kind)))
+	}
+
+	// 5. 只启用部分检查
+	fmt.Println("\n5. 只启用部分检查")
+	extra := eorm.NewRecord().FromJson(`{"order_id": 1003, "status": "paid", "coupon": "SPRING", "customer": {"id": 3, "name": "王五"}}`)
+	if err := ToStructWithOptions(extra, &Order{}, StrictOptions{CheckRequired: true}); err == nil {
+		fmt.Println("   ✅ 允许未知列: coupon 被忽略")
+	}
+	if err := ToStructWithOptions(extra, &Order{}, StrictOptions{DisallowUnknown: true}); err != nil {
+		fmt.Printf("   禁止未知列 (预期): %v\n", err)
+	}
+
+	// 6. 类型不匹配总是报告
+	fmt.Println("\n6. 类型不匹配总是报告")
+	if err := ToStructWithOptions(eorm.NewRecord().Set("items", "none"), &Order{}, StrictOptions{}); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+
+	// 7. 未导出的嵌入结构体指针
+	fmt.Println("\n7. 未导出的嵌入结构体指针")
+	note := eorm.NewRecord().Set("title", "备忘").Set("updated_by", "admin")
+	if err := ToStructStrict(note, &Note{}); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	withAudit := Note{audit: &audit{}}
+	if err := ToStructStrict(note, &withAudit); err == nil {
+		fmt.Printf("   已分配时正常赋值: UpdatedBy=%s\n", withAudit.UpdatedBy)
+	}
+
+	// 8. 超出 2^53 的整数
+	fmt.Println("\n8. 超出 2^53 的整数")
+	big := eorm.NewRecord().Set("amount", int64(1<<53+1))
+	if err := ToStructWithOptions(big, &Order{}, StrictOptions{DisallowLossy: true}); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
+
+func firstQty(items []Item) int8 {
+	if len(items) == 0 {
+		return 0
+	}
+	return items[0].Qty
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	recordType = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// ProblemKind 是 FieldError 的类别
+type ProblemKind string
+
+const (
+	ProblemUnknown  ProblemKind = "unknown"  // Record 中有结构体没有声明的列
+	ProblemRequired ProblemKind = "required" // validate:"required" 的字段不存在或为 null
+	ProblemType     ProblemKind = "type"     // 值无法转换为字段类型
+	ProblemLossy    ProblemKind = "lossy"    // 转换会丢失信息，例如 3.7 -> int、300 -> int8
+)
+
+// FieldError 描述一个字段的问题，Path 是结构体中的点分路径，例如 Customer.Address.City、Items[1].Qty
+// 未知列的路径由所在结构体的路径加上列名组成，例如 Customer.nickname
+type FieldError struct {
+	Path    string
+	Kind    ProblemKind
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, e.Kind)
+}
+
+// FieldErrors 是 ToStructStrict 返回的全部问题，按发现的顺序排列
+type FieldErrors []FieldError
+
+func (errs FieldErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("strict: %d problem(s): %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// ByKind 返回指定类别的问题
+func (errs FieldErrors) ByKind(kind ProblemKind) FieldErrors {
+	var out FieldErrors
+	for _, e := range errs {
+		if e.Kind == kind {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// StrictOptions 控制 ToStructWithOptions 检查哪些问题，类型不匹配总是报告
+type StrictOptions struct {
+	DisallowUnknown bool // 报告结构体没有声明的列
+	CheckRequired   bool // 报告 validate:"required" 的字段缺失或为 null
+	DisallowLossy   bool // 报告丢失信息的转换
+}
+
+// ToStructStrict 启用全部检查，等同于 ToStructWithOptions(r, dest, StrictOptions{true, true, true})
+func ToStructStrict(r *eorm.Record, dest interface{}) error {
+	return ToStructWithOptions(r, dest, StrictOptions{DisallowUnknown: true, CheckRequired: true, DisallowLossy: true})
+}
+
+// ToStructWithOptions 将 Record 转换为结构体，并一次返回所有问题
+// 列名规则与 eorm.ToStruct 一致：依次使用 column、db、json 标签，没有标签时使用小写的字段名，匹配不区分大小写；
+// 没有列名的匿名嵌入结构体展开到上一层
+//
+// 有问题的字段保持原值（丢失信息的转换仍会写入转换后的值），其他字段正常赋值；
+// 没有问题时返回 nil，否则返回 FieldErrors
+func ToStructWithOptions(r *eorm.Record, dest interface{}, opts StrictOptions) error {
+	if r == nil {
+		return fmt.Errorf("strict: record is nil")
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("strict: dest must be a non-nil pointer to a struct, got %T", dest)
+	}
+	d := decoder{opts: opts}
+	d.decodeStruct(r, v.Elem(), "")
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+type decoder struct {
+	opts StrictOptions
+	errs FieldErrors
+}
+
+func (d *decoder) report(path string, kind ProblemKind, format string, args ...interface{}) {
+	d.errs = append(d.errs, FieldError{Path: path, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+type structField struct {
+	column   string
+	name     string // Go 字段名，用于错误路径
+	index    []int
+	required bool
+}
+
+// fieldsOf 按 eorm 的规则解析列名，匿名嵌入的结构体展开到上一层
+func fieldsOf(t reflect.Type, prefix []int) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := ""
+		for _, tag := range []string{"column", "db", "json"} {
+			if v := f.Tag.Get(tag); v != "" {
+				name = strings.TrimSpace(strings.Split(v, ",")[0])
+				break
+			}
+		}
+		if name == "-" {
+			continue
+		}
+		index := append(append([]int{}, prefix...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			fields = append(fields, fieldsOf(ft, index)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		required := false
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			if strings.TrimSpace(rule) == "required" {
+				required = true
+			}
+		}
+		fields = append(fields, structField{column: name, name: f.Name, index: index, required: required})
+	}
+	return fields
+}
+
+func (d *decoder) decodeStruct(r *eorm.Record, v reflect.Value, path string) {
+	fields := fieldsOf(v.Type(), nil)
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[strings.ToLower(f.column)] = true
+		fieldPath := joinPath(path, f.name)
+		if !r.Has(f.column) || r.Get(f.column) == nil {
+			if f.required && d.opts.CheckRequired {
+				d.report(fieldPath, ProblemRequired, "column '%s' is required", f.column)
+			}
+			if !r.Has(f.column) {
+				continue
+			}
+		}
+		dst, err := fieldByIndexAlloc(v, f.index)
+		if err != nil {
+			d.report(fieldPath, ProblemType, "%v", err)
+			continue
+		}
+		d.decodeValue(r.Get(f.column), dst, fieldPath)
+	}
+	if d.opts.DisallowUnknown {
+		for _, key := range r.Keys() {
+			if !known[strings.ToLower(key)] {
+				d.report(joinPath(path, key), ProblemUnknown, "column '%s' is not declared in %s", key, v.Type())
+			}
+		}
+	}
+}
+
+// fieldByIndexAlloc 与 reflect.Value.FieldByIndex 相同，路径上为 nil 的指针会被分配
+// 未导出类型的嵌入结构体指针为 nil 时无法分配，与 encoding/json 一样返回错误
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// onlyLossy 判断 errs 中是否只有丢失信息的问题，此时转换后的值仍会写入
+func onlyLossy(errs FieldErrors) bool {
+	for _, e := range errs {
+		if e.Kind != ProblemLossy {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *decoder) mismatch(path string, value interface{}, t reflect.Type) {
+	d.report(path, ProblemType, "cannot convert %T (%v) to %s", value, preview(value), t)
+}
+
+func preview(v interface{}) string {
+	if rec := asRecord(v); rec != nil {
+		return "object"
+	}
+	s := fmt.Sprint(v)
+	if len(s) > 32 {
+		s = s[:29] + "..."
+	}
+	return s
+}
+
+func (d *decoder) decodeValue(value interface{}, dst reflect.Value, path string) {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return
+	}
+	src := reflect.ValueOf(value)
+	if src.Type() == dst.Type() && dst.Kind() != reflect.Struct && dst.Kind() != reflect.Slice {
+		dst.Set(src)
+		return
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		before := len(d.errs)
+		d.decodeValue(value, elem.Elem(), path)
+		if onlyLossy(d.errs[before:]) {
+			dst.Set(elem)
+		}
+	case reflect.Interface:
+		if src.Type().Implements(dst.Type()) {
+			dst.Set(src)
+			return
+		}
+		d.mismatch(path, value, dst.Type())
+	case reflect.Struct:
+		d.decodeStructValue(value, dst, path)
+	case reflect.Slice:
+		d.decodeSlice(value, dst, path)
+	case reflect.Map:
+		d.decodeMap(value, dst, path)
+	case reflect.String:
+		switch val := value.(type) {
+		case string:
+			dst.SetString(val)
+		case []byte:
+			dst.SetString(string(val))
+		case json.Number:
+			dst.SetString(string(val))
+		default:
+			// 数字和布尔值转为字符串不会丢失信息
+			switch src.Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+				dst.SetString(eorm.Convert.ToString(value))
+			default:
+				d.mismatch(path, value, dst.Type())
+			}
+		}
+	case reflect.Bool:
+		b, err := eorm.Convert.ToBoolWithError(value)
+		if err != nil || (src.Kind() != reflect.Bool && src.Kind() != reflect.String) {
+			d.mismatch(path, value, dst.Type())
+			return
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		d.decodeNumber(value, dst, path)
+	default:
+		if src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+			return
+		}
+		d.mismatch(path, value, dst.Type())
+	}
+}
+
+func (d *decoder) decodeStructValue(value interface{}, dst reflect.Value, path string) {
+	if reflect.TypeOf(value) == dst.Type() {
+		dst.Set(reflect.ValueOf(value))
+		return
+	}
+	if dst.Type() == timeType {
+		t, err := eorm.Convert.ToTimeWithError(value)
+		if err != nil {
+			d.mismatch(path, value, dst.Type())
+			return
+		}
+		dst.Set(reflect.ValueOf(t))
+		return
+	}
+	rec := asRecord(value)
+	if rec == nil {
+		d.mismatch(path, value, dst.Type())
+		return
+	}
+	d.decodeStruct(rec, dst, path)
+}
+
+func (d *decoder) decodeSlice(value interface{}, dst reflect.Value, path string) {
+	src := reflect.ValueOf(value)
+	if s, ok := value.(string); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+		dst.SetBytes([]byte(s))
+		return
+	}
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		d.mismatch(path, value, dst.Type())
+		return
+	}
+	out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		d.decodeValue(src.Index(i).Interface(), out.Index(i), fmt.Sprintf("%s[%d]", path, i))
+	}
+	dst.Set(out)
+}
+
+func (d *decoder) decodeMap(value interface{}, dst reflect.Value, path string) {
+	if dst.Type().Key().Kind() != reflect.String {
+		d.mismatch(path, value, dst.Type())
+		return
+	}
+	rec := asRecord(value)
+	if rec == nil {
+		src := reflect.ValueOf(value)
+		if src.Kind() != reflect.Map || src.Type().Key().Kind() != reflect.String {
+			d.mismatch(path, value, dst.Type())
+			return
+		}
+		rec = eorm.NewRecord()
+		iter := src.MapRange()
+		for iter.Next() {
+			rec.Set(iter.Key().String(), iter.Value().Interface())
+		}
+	}
+	out := reflect.MakeMapWithSize(dst.Type(), len(rec.Keys()))
+	for _, key := range rec.Keys() {
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		d.decodeValue(rec.Get(key), elem, fmt.Sprintf("%s[%s]", path, key))
+		out.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+	}
+	dst.Set(out)
+}
+
+// decodeNumber 转换数值，字符串和 json.Number 按数字解析
+// 丢失信息的情况：小数部分被截断、超出目标类型范围、负数转无符号整数、整数超过 float64 的精确范围、float32 无法保留十进制表示
+func (d *decoder) decodeNumber(value interface{}, dst reflect.Value, path string) {
+	var (
+		isInt bool
+		i     int64
+		u     uint64
+		f     float64
+		isU   bool
+	)
+	src := reflect.ValueOf(value)
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		isInt, i, f = true, src.Int(), float64(src.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		isInt, isU, u, f = true, true, src.Uint(), float64(src.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = src.Float()
+	case reflect.String:
+		s := strings.TrimSpace(src.String())
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			isInt, i, f = true, n, float64(n)
+		} else if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			isInt, isU, u, f = true, true, n, float64(n)
+		} else if n, err := strconv.ParseFloat(s, 64); err == nil {
+			f = n
+		} else {
+			d.mismatch(path, value, dst.Type())
+			return
+		}
+	default:
+		d.mismatch(path, value, dst.Type())
+		return
+	}
+
+	lossy := func(format string, args ...interface{}) {
+		if d.opts.DisallowLossy {
+			d.report(path, ProblemLossy, format, args...)
+		}
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := i
+		switch {
+		case isU:
+			if u > math.MaxInt64 {
+				lossy("%d overflows %s", u, dst.Type())
+			}
+			n = int64(u)
+		case !isInt:
+			if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				lossy("%v overflows %s", f, dst.Type())
+			} else if f != math.Trunc(f) {
+				lossy("%v has a fractional part, truncated to %d", f, int64(f))
+			}
+			n = int64(f)
+		}
+		if dst.OverflowInt(n) {
+			lossy("%d overflows %s", n, dst.Type())
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := u
+		switch {
+		case isInt && !isU:
+			if i < 0 {
+				lossy("negative value %d cannot be stored in %s", i, dst.Type())
+			}
+			n = uint64(i)
+		case !isInt:
+			if f < 0 {
+				lossy("negative value %v cannot be stored in %s", f, dst.Type())
+			} else if f >= math.MaxUint64 || math.IsNaN(f) {
+				lossy("%v overflows %s", f, dst.Type())
+			} else if f != math.Trunc(f) {
+				lossy("%v has a fractional part, truncated to %d", f, uint64(f))
+			}
+			n = uint64(f)
+		}
+		if dst.OverflowUint(n) {
+			lossy("%d overflows %s", n, dst.Type())
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		// 在转换为 float64 之前检查原始整数，2^53+1 转换后已经变成 2^53
+		if (isU && u > 1<<53) || (isInt && !isU && (i > 1<<53 || i < -(1<<53))) {
+			lossy("%v cannot be represented exactly as %s", value, dst.Type())
+		}
+		// 0.1 这类十进制字面量转换为 float32 后仍然输出 0.1，不视为丢失信息
+		if dst.Kind() == reflect.Float32 && strconv.FormatFloat(f, 'g', -1, 32) != strconv.FormatFloat(f, 'g', -1, 64) {
+			lossy("%v cannot be represented as float32", f)
+		}
+		dst.SetFloat(f)
+	}
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	recordType    = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+	recordPtrType = reflect.TypeOf((*eorm.Record)(nil))
+	mapAnyType    = reflect.TypeOf(map[string]interface{}(nil))
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// structInfo 是结构体展开后的字段映射
+type structInfo struct {
+	fields []fieldInfo
+	byKey  map[string]int // 小写列名 -> fields 下标
+	remain []int          // remain 字段的下标路径，没有时为 nil
+}
+
+type fieldInfo struct {
+	column string
+	index  []int
+	depth  int
+}
+
+var structCache sync.Map // reflect.Type -> *structInfo
+
+// tagName 按 eorm、column、db、json 的顺序读取列名和选项
+// eorm 标签排在最前，用于声明 remain 等选项，例如 `eorm:",remain"`
+func tagName(f reflect.StructField) (string, map[string]bool) {
+	for _, key := range []string{"eorm", "column", "db", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		opts := make(map[string]bool)
+		for _, opt := range parts[1:] {
+			opts[strings.TrimSpace(opt)] = true
+		}
+		return strings.TrimSpace(parts[0]), opts
+	}
+	return "", map[string]bool{}
+}
+
+// isRemainType 判断 remain 字段的类型是否受支持
+func isRemainType(t reflect.Type) bool {
+	return t == mapAnyType || t == recordPtrType || t == recordType
+}
+
+// infoOf 解析结构体字段
+//
+// 规则：
+//  1. 没有列名的匿名嵌入结构体（含指针）展开到上一层，可以多层嵌套
+//  2. 多个字段映射到同一列时，层级较浅的字段优先，与 Go 的字段提升规则一致
+//  3. 标签带 remain 选项的字段接收未映射的列，类型必须是 map[string]interface{}、*eorm.Record 或 eorm.Record；
+//     嵌入结构体中的 remain 字段同样有效，外层的优先
+func infoOf(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structCache.Load(t); ok {
+		return cached.(*structInfo), nil
+	}
+	info := &structInfo{byKey: make(map[string]int)}
+	remainDepth := -1
+	var walk func(t reflect.Type, prefix []int, visited map[reflect.Type]bool) error
+	walk = func(t reflect.Type, prefix []int, visited map[reflect.Type]bool) error {
+		if visited[t] {
+			return nil
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts := tagName(f)
+			if name == "-" {
+				continue
+			}
+			index := append(append([]int{}, prefix...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr && ft != recordPtrType {
+				ft = ft.Elem()
+			}
+			if opts["remain"] {
+				if !isRemainType(f.Type) {
+					return fmt.Errorf("embedded: remain field %s.%s must be map[string]interface{}, *eorm.Record or eorm.Record, got %s", t, f.Name, f.Type)
+				}
+				if remainDepth == -1 || len(index) < remainDepth {
+					info.remain, remainDepth = index, len(index)
+				}
+				continue
+			}
+			// 与 encoding/json 一致，跳过未导出的嵌入结构体指针：ToStruct 无法为它分配值
+			if f.Anonymous && !f.IsExported() && f.Type.Kind() == reflect.Ptr {
+				continue
+			}
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType && ft != recordType {
+				if err := walk(ft, index, visited); err != nil {
+					return err
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			key := strings.ToLower(name)
+			if j, exists := info.byKey[key]; exists {
+				if info.fields[j].depth <= len(index) {
+					continue
+				}
+				info.fields[j] = fieldInfo{column: name, index: index, depth: len(index)}
+				continue
+			}
+			info.byKey[key] = len(info.fields)
+			info.fields = append(info.fields, fieldInfo{column: name, index: index, depth: len(index)})
+		}
+		return nil
+	}
+	if err := walk(t, nil, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	structCache.Store(t, info)
+	return info, nil
+}
+
+// FromStruct 从结构体创建 Record
+// 嵌入结构体的字段展开到同一层，remain 字段中的键展开到 Record 中，与已映射的列同名时以字段为准
+func FromStruct(src interface{}) (*eorm.Record, error) {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("embedded: src is nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("embedded: src must be a struct or pointer to struct, got %T", src)
+	}
+	return fromStruct(v)
+}
+
+func fromStruct(v reflect.Value) (*eorm.Record, error) {
+	info, err := infoOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	rec := eorm.NewRecord()
+	for _, f := range info.fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		value, err := fromValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		rec.Set(f.column, value)
+	}
+	if info.remain != nil {
+		if fv, ok := fieldByIndex(v, info.remain); ok {
+			extra := remainRecord(fv)
+			for _, key := range extra.Keys() {
+				if _, mapped := info.byKey[strings.ToLower(key)]; mapped {
+					continue
+				}
+				rec.Set(key, extra.Get(key))
+			}
+		}
+	}
+	return rec, nil
+}
+
+// remainRecord 将 remain 字段转换为 *eorm.Record，字段为 nil 时返回空 Record
+// map 没有顺序，按键排序后展开，使 FromStruct 的结果稳定
+func remainRecord(fv reflect.Value) *eorm.Record {
+	switch fv.Type() {
+	case mapAnyType:
+		rec := eorm.NewRecord()
+		m := fv.Interface().(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			rec.Set(k, m[k])
+		}
+		return rec
+	case recordPtrType:
+		if fv.IsNil() {
+			return eorm.NewRecord()
+		}
+		return fv.Interface().(*eorm.Record)
+	}
+	return asRecord(fv.Interface())
+}
+
+func fromValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type() == recordPtrType {
+			return v.Interface(), nil
+		}
+		return fromValue(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType || v.Type() == recordType {
+			return v.Interface(), nil
+		}
+		return fromStruct(v)
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct || elem == timeType || elem == recordType {
+			return v.Interface(), nil
+		}
+		if v.IsNil() {
+			return nil, nil
+		}
+		recs := make([]*eorm.Record, v.Len())
+		for i := range recs {
+			item, err := fromValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			recs[i], _ = item.(*eorm.Record)
+		}
+		return recs, nil
+	}
+	return v.Interface(), nil
+}
+
+// fieldByIndex 与 reflect.Value.FieldByIndex 相同，路径上的指针为 nil 时返回 false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc 与 fieldByIndex 相同，路径上为 nil 的指针会被分配
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// ToStruct 将 Record 转换为结构体，dest 必须是结构体指针
+// 嵌入结构体的字段从同一层读取；没有映射到字段的列写入 remain 字段，替换其原有内容，没有这样的列时 remain 字段保持原值
+func ToStruct(r *eorm.Record, dest interface{}) error {
+	if r == nil {
+		return fmt.Errorf("embedded: record is nil")
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("embedded: dest must be a non-nil pointer to a struct, got %T", dest)
+	}
+	return toStruct(r, v.Elem(), "")
+}
+
+func toStruct(r *eorm.Record, v reflect.Value, path string) error {
+	info, err := infoOf(v.Type())
+	if err != nil {
+		return err
+	}
+	extra := eorm.NewRecord()
+	for _, key := range r.Keys() {
+		j, ok := info.byKey[strings.ToLower(key)]
+		if !ok {
+			extra.Set(key, r.Get(key))
+			continue
+		}
+		f := info.fields[j]
+		if err := assign(fieldByIndexAlloc(v, f.index), r.Get(key), joinPath(path, f.column)); err != nil {
+			return err
+		}
+	}
+	if info.remain == nil || len(extra.Keys()) == 0 {
+		return nil
+	}
+	fv := fieldByIndexAlloc(v, info.remain)
+	switch fv.Type() {
+	case mapAnyType:
+		fv.Set(reflect.ValueOf(extra.ToMap()))
+	case recordPtrType:
+		fv.Set(reflect.ValueOf(extra))
+	default:
+		fv.Set(reflect.ValueOf(extra).Elem())
+	}
+	return nil
+}
+
+// assign 将 value 转换后写入 dst，基本类型使用 eorm.Convert，嵌套结构体按同样的规则递归
+func assign(dst reflect.Value, value interface{}, path string) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) && dst.Kind() != reflect.Struct {
+		dst.Set(src)
+		return nil
+	}
+	fail := func(err error) error {
+		return fmt.Errorf("embedded: '%s': %v", path, err)
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.Type() == recordPtrType {
+			if rec := asRecord(value); rec != nil {
+				dst.Set(reflect.ValueOf(rec))
+				return nil
+			}
+			break
+		}
+		elem := reflect.New(dst.Type().Elem())
+		if err := assign(elem.Elem(), value, path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Struct:
+		if src.Type() == dst.Type() {
+			dst.Set(src)
+			return nil
+		}
+		if dst.Type() == timeType {
+			t, err := eorm.Convert.ToTimeWithError(value)
+			if err != nil {
+				return fail(err)
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		rec := asRecord(value)
+		if rec == nil {
+			break
+		}
+		if dst.Type() == recordType {
+			dst.Set(reflect.ValueOf(rec).Elem())
+			return nil
+		}
+		return toStruct(rec, dst, path)
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := assign(out.Index(i), src.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Map:
+		if dst.Type() == mapAnyType {
+			if rec := asRecord(value); rec != nil {
+				dst.Set(reflect.ValueOf(rec.ToMap()))
+				return nil
+			}
+		}
+	case reflect.String:
+		s, err := eorm.Convert.ToStringWithError(value)
+		if err != nil {
+			return fail(err)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := eorm.Convert.ToBoolWithError(value)
+		if err != nil {
+			return fail(err)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := eorm.Convert.ToInt64WithError(value)
+		if err != nil {
+			return fail(err)
+		}
+		if dst.OverflowInt(n) {
+			return fail(fmt.Errorf("value %d overflows %s", n, dst.Type()))
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := eorm.Convert.ToUint64WithError(value)
+		if err != nil {
+			return fail(err)
+		}
+		if dst.OverflowUint(n) {
+			return fail(fmt.Errorf("value %d overflows %s", n, dst.Type()))
+		}
+		dst.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := eorm.Convert.ToFloat64WithError(value)
+		if err != nil {
+			return fail(err)
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	if src.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+	return fail(fmt.Errorf("cannot convert %T to %s", value, dst.Type()))
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// BaseModel 是所有模型共用的字段
+type BaseModel struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SoftDelete 通过指针嵌入，未删除的记录为 nil
+type SoftDelete struct {
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type Product struct {
+	BaseModel
+	*SoftDelete
+	Name  string                 `json:"name"`
+	Price float64                `json:"price"`
+	Extra map[string]interface{} `eorm:",remain"`
+}
+
+// Event 使用 *eorm.Record 接收未声明的列，并覆盖 BaseModel 中的 ID
+type Event struct {
+	BaseModel
+	ID      string       `json:"id"`
+	Type    string       `json:"type"`
+	Payload *eorm.Record `json:"payload"`
+	Attrs   *eorm.Record `eorm:",remain"`
+}
+
+// Order 演示嵌套结构体中的 remain 字段
+type Order struct {
+	BaseModel
+	Customer Customer `json:"customer"`
+}
+
+type Customer struct {
+	Name  string                 `json:"name"`
+	Other map[string]interface{} `eorm:",remain"`
+}
+
+// audit 是未导出的类型，以指针嵌入时被跳过
+type audit struct {
+	UpdatedBy string `json:"updated_by"`
+}
+
+// Note 通过未导出的指针嵌入 audit
+type Note struct {
+	*audit
+	Title string `json:"title"`
+}
+
+// 示例31：嵌入结构体展开和 remain 字段
+// 演示匿名嵌入结构体展开到同一层，以及用 remain 字段接收未映射的列，FromStruct 时再展开回 Record
+func main() {
+	fmt.Println("========== 嵌入结构体示例 ==========")
+
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	product := Product{
+		BaseModel: BaseModel{ID: 1, CreatedAt: created},
+		Name:      "键盘",
+		Price:     299,
+		Extra:     map[string]interface{}{"color": "black", "weight_g": 850, "name": "被字段覆盖"},
+	}
+
+	// 1. eorm.ToRecord 的行为
+	fmt.Println("\n1. eorm.ToRecord 的行为")
+	fmt.Printf("   %s\n", eorm.ToRecord(product).ToJson())
+
+	// 2. FromStruct 展开嵌入结构体和 remain 字段
+	fmt.Println("\n2. FromStruct")
+	record, _ := FromStruct(product)
+	fmt.Printf("   %s\n", record.ToJson())
+	fmt.Println("   SoftDelete 为 nil，deleted_at 不输出；Extra 中的 name 与字段同名，以字段为准")
+
+	// 3. ToStruct 收集未映射的列
+	fmt.Println("\n3. ToStruct")
+	row := eorm.NewRecord().FromJson(`{"id": 2, "created_at": "2024-05-02T08:00:00Z", "deleted_at": "2024-06-01T00:00:00Z", "name": "鼠标", "price": 99, "color": "white", "dpi": 1600}`)
+	var loaded Product
+	if err := ToStruct(row, &loaded); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	fmt.Printf("   ID=%d CreatedAt=%s Name=%s Price=%v\n", loaded.ID, loaded.CreatedAt.Format(time.RFC3339), loaded.Name, loaded.Price)
+	fmt.Printf("   SoftDelete 已分配: DeletedAt=%s\n", loaded.DeletedAt.Format(time.RFC3339))
+	fmt.Printf("   Extra: color=%v dpi=%v\n", loaded.Extra["color"], loaded.Extra["dpi"])
+
+	// 4. 往返
+	fmt.Println("\n4. 往返")
+	again, _ := FromStruct(loaded)
+	fmt.Printf("   %s\n", again.ToJson())
+
+	// 5. *eorm.Record 类型的 remain 字段和字段遮蔽
+	fmt.Println("\n5. *eorm.Record 类型的 remain 字段")
+	eventRow := eorm.NewRecord().
+		Set("id", "evt-1").
+		Set("type", "order.paid").
+		Set("payload", eorm.NewRecord().Set("order_id", 1001)).
+		Set("trace_id", "abc").
+		Set("source", "api")
+	var event Event
+	if err := ToStruct(eventRow, &event); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	fmt.Printf("   ID=%s (Event.ID 遮蔽 BaseModel.ID=%d) Type=%s\n", event.ID, event.BaseModel.ID, event.Type)
+	fmt.Printf("   Payload=%s Attrs=%s\n", event.Payload.ToJson(), event.Attrs.ToJson())
+	eventRecord, _ := FromStruct(event)
+	fmt.Printf("   FromStruct: %s\n", eventRecord.ToJson())
+
+	// 6. 嵌套结构体中的 remain 字段
+	fmt.Println("\n6. 嵌套结构体中的 remain 字段")
+	orderRow := eorm.NewRecord().FromJson(`{"id": 3, "customer": {"name": "张三", "vip": true}}`)
+	var order Order
+	_ = ToStruct(orderRow, &order)
+	fmt.Printf("   Customer.Name=%s Customer.Other=%v\n", order.Customer.Name, order.Customer.Other)
+	orderRecord, _ := FromStruct(order)
+	fmt.Printf("   FromStruct: %s\n", orderRecord.ToJson())
+
+	// 7. 未导出的嵌入结构体指针
+	fmt.Println("\n7. 未导出的嵌入结构体指针")
+	var note Note
+	if err := ToStruct(eorm.NewRecord().Set("title", "备忘").Set("updated_by", "admin"), &note); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	fmt.Printf("   Title=%s audit=%v\n", note.Title, note.audit)
+	noteRecord, _ := FromStruct(Note{audit: &audit{UpdatedBy: "admin"}, Title: "备忘"})
+	fmt.Printf("   FromStruct: %s\n", noteRecord.ToJson())
+	fmt.Println("   与 encoding/json 一致，*audit 被跳过，不会因为无法分配而 panic")
+
+	// 8. 错误处理
+	fmt.Println("\n8. 错误处理")
+	type BadRemain struct {
+		Extra []string `eorm:",remain"`
+	}
+	if _, err := FromStruct(BadRemain{}); err != nil {
+		fmt.Printf("   remain 类型错误 (预期): %v\n", err)
+	}
+	if err := ToStruct(eorm.NewRecord().Set("id", "x"), &Product{}); err != nil {
+		fmt.Printf("   嵌入字段类型不匹配 (预期): %v\n", err)
+	}
+	type Sensor struct {
+		BaseModel
+		Level int8 `json:"level"`
+	}
+	if err := ToStruct(eorm.NewRecord().Set("level", 300), &Sensor{}); err != nil {
+		fmt.Printf("   整数溢出 (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	recordType    = reflect.TypeOf((*eorm.Record)(nil)).Elem()
+	recordPtrType = reflect.TypeOf((*eorm.Record)(nil))
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// decodeFunc 将 Record 中的值写入字段，在生成计划时按字段类型选定，转换每一行时不再判断类型
+type decodeFunc func(v interface{}, dst reflect.Value) error
+
+// encodeFunc 将字段的值转换为 Record 中保存的值
+type encodeFunc func(src reflect.Value) interface{}
+
+// structPlan 是一个结构体类型的转换计划，同一类型只生成一次
+type structPlan struct {
+	typ    reflect.Type
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	column string
+	index  int
+	decode decodeFunc
+	encode encodeFunc
+}
+
+var (
+	plans     sync.Map // reflect.Type -> *structPlan
+	planStats struct {
+		sync.Mutex
+		built int
+	}
+)
+
+// PlansBuilt 返回已生成的转换计划数量，用于确认计划被复用
+func PlansBuilt() int {
+	planStats.Lock()
+	defer planStats.Unlock()
+	return planStats.built
+}
+
+// planFor 返回 t 的转换计划，首次调用时生成并缓存
+// 嵌套结构体的计划在第一次转换该字段时才生成，因此生成计划时不会递归，引用自身的类型也可以处理
+func planFor(t reflect.Type) *structPlan {
+	if cached, ok := plans.Load(t); ok {
+		return cached.(*structPlan)
+	}
+	p := &structPlan{typ: t}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		column := columnName(f)
+		if column == "-" {
+			continue
+		}
+		p.fields = append(p.fields, fieldPlan{
+			column: column,
+			index:  i,
+			decode: decoderFor(f.Type),
+			encode: encoderFor(f.Type),
+		})
+	}
+	// 并发生成同一类型时只保留先存入的计划
+	if actual, loaded := plans.LoadOrStore(t, p); loaded {
+		return actual.(*structPlan)
+	}
+	planStats.Lock()
+	planStats.built++
+	planStats.Unlock()
+	return p
+}
+
+// columnName 与 eorm 一致：依次使用 column、db、json 标签，没有标签时使用小写的字段名
+func columnName(f reflect.StructField) string {
+	for _, key := range []string{"column", "db", "json"} {
+		if tag := f.Tag.Get(key); tag != "" {
+			return strings.TrimSpace(strings.Split(tag, ",")[0])
+		}
+	}
+	return strings.ToLower(f.Name)
+}
+
+// decoderFor 按字段类型选定转换函数，常见的值类型直接赋值，其余使用 eorm.Convert
+func decoderFor(t reflect.Type) decodeFunc {
+	switch t {
+	case timeType:
+		return func(v interface{}, dst reflect.Value) error {
+			if tm, ok := v.(time.Time); ok {
+				dst.Set(reflect.ValueOf(tm))
+				return nil
+			}
+			tm, err := eorm.Convert.ToTimeWithError(v)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(tm))
+			return nil
+		}
+	case recordPtrType, recordType:
+		return func(v interface{}, dst reflect.Value) error {
+			rec := asRecord(v)
+			if rec == nil {
+				return fmt.Errorf("cannot convert %T to %s", v, t)
+			}
+			if t == recordType {
+				dst.Set(reflect.ValueOf(rec).Elem())
+				return nil
+			}
+			dst.Set(reflect.ValueOf(rec))
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v interface{}, dst reflect.Value) error {
+			if s, ok := v.(string); ok {
+				dst.SetString(s)
+				return nil
+			}
+			s, err := eorm.Convert.ToStringWithError(v)
+			if err != nil {
+				return err
+			}
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		return func(v interface{}, dst reflect.Value) error {
+			if b, ok := v.(bool); ok {
+				dst.SetBool(b)
+				return nil
+			}
+			b, err := eorm.Convert.ToBoolWithError(v)
+			if err != nil {
+				return err
+			}
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v interface{}, dst reflect.Value) error {
+			var n int64
+			switch val := v.(type) {
+			case int64:
+				n = val
+			case int:
+				n = int64(val)
+			default:
+				var err error
+				if n, err = eorm.Convert.ToInt64WithError(v); err != nil {
+					return err
+				}
+			}
+			if dst.OverflowInt(n) {
+				return fmt.Errorf("value %d overflows %s", n, dst.Type())
+			}
+			dst.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v interface{}, dst reflect.Value) error {
+			n, err := eorm.Convert.ToUint64WithError(v)
+			if err != nil {
+				return err
+			}
+			if dst.OverflowUint(n) {
+				return fmt.Errorf("value %d overflows %s", n, dst.Type())
+			}
+			dst.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(v interface{}, dst reflect.Value) error {
+			f, ok := v.(float64)
+			if !ok {
+				var err error
+				if f, err = eorm.Convert.ToFloat64WithError(v); err != nil {
+					return err
+				}
+			}
+			if dst.OverflowFloat(f) {
+				return fmt.Errorf("value %v overflows %s", f, dst.Type())
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+	case reflect.Ptr:
+		elem := decoderFor(t.Elem())
+		return func(v interface{}, dst reflect.Value) error {
+			ptr := reflect.New(t.Elem())
+			if err := elem(v, ptr.Elem()); err != nil {
+				return err
+			}
+			dst.Set(ptr)
+			return nil
+		}
+	case reflect.Struct:
+		return func(v interface{}, dst reflect.Value) error {
+			rec := asRecord(v)
+			if rec == nil {
+				return fmt.Errorf("cannot convert %T to %s", v, t)
+			}
+			return planFor(t).decode(rec, dst)
+		}
+	case reflect.Slice:
+		elem := decoderFor(t.Elem())
+		return func(v interface{}, dst reflect.Value) error {
+			src := reflect.ValueOf(v)
+			if src.Type() == t {
+				// 复制切片，避免结构体与 Record 共用底层数组
+				if src.IsNil() {
+					dst.Set(reflect.Zero(t))
+				} else {
+					dst.Set(reflect.AppendSlice(reflect.MakeSlice(t, 0, src.Len()), src))
+				}
+				return nil
+			}
+			if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+				return fmt.Errorf("cannot convert %T to %s", v, t)
+			}
+			out := reflect.MakeSlice(t, src.Len(), src.Len())
+			for i := 0; i < src.Len(); i++ {
+				item := src.Index(i).Interface()
+				if item == nil {
+					continue
+				}
+				if err := elem(item, out.Index(i)); err != nil {
+					return fmt.Errorf("[%d]: %v", i, err)
+				}
+			}
+			dst.Set(out)
+			return nil
+		}
+	}
+	return func(v interface{}, dst reflect.Value) error {
+		src := reflect.ValueOf(v)
+		if src.Type().AssignableTo(t) {
+			dst.Set(src)
+			return nil
+		}
+		if src.Type().ConvertibleTo(t) {
+			dst.Set(src.Convert(t))
+			return nil
+		}
+		return fmt.Errorf("cannot convert %T to %s", v, t)
+	}
+}
+
+// encoderFor 与 eorm.FromStruct 一致：嵌套结构体转换为 *Record，结构体切片转换为 []*Record，其他值原样保存
+func encoderFor(t reflect.Type) encodeFunc {
+	switch {
+	case t == timeType || t == recordPtrType || t == recordType:
+		return func(src reflect.Value) interface{} { return src.Interface() }
+	case t.Kind() == reflect.Ptr:
+		elem := encoderFor(t.Elem())
+		return func(src reflect.Value) interface{} {
+			if src.IsNil() {
+				return nil
+			}
+			return elem(src.Elem())
+		}
+	case t.Kind() == reflect.Struct:
+		return func(src reflect.Value) interface{} {
+			return planFor(t).encode(src)
+		}
+	case t.Kind() == reflect.Slice && isPlainStruct(t.Elem()):
+		elem := encoderFor(t.Elem())
+		return func(src reflect.Value) interface{} {
+			if src.IsNil() {
+				return nil
+			}
+			recs := make([]*eorm.Record, src.Len())
+			for i := range recs {
+				recs[i], _ = elem(src.Index(i)).(*eorm.Record)
+			}
+			return recs
+		}
+	}
+	return func(src reflect.Value) interface{} { return src.Interface() }
+}
+
+// isPlainStruct 判断 t 是否为需要转换为 Record 的结构体或结构体指针，time.Time 和 Record 除外
+func isPlainStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && t != recordType
+}
+
+// decode 按计划将 Record 写入结构体，Record 中不存在或为 nil 的列保持字段零值
+func (p *structPlan) decode(r *eorm.Record, dst reflect.Value) error {
+	for _, f := range p.fields {
+		v := r.Get(f.column)
+		if v == nil {
+			continue
+		}
+		if err := f.decode(v, dst.Field(f.index)); err != nil {
+			return fmt.Errorf("field '%s': %v", f.column, err)
+		}
+	}
+	return nil
+}
+
+// encode 按计划将结构体转换为 Record，字段顺序与结构体声明一致
+func (p *structPlan) encode(src reflect.Value) *eorm.Record {
+	rec := eorm.NewRecord()
+	for _, f := range p.fields {
+		rec.Set(f.column, f.encode(src.Field(f.index)))
+	}
+	return rec
+}
+
+// RowError 记录批量转换中出错的行
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("batch: row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ToStructs 将 Query 返回的 []*Record 转换为 []T，T 可以是结构体或结构体指针
+// 转换计划按类型缓存，所有行共用；遇到第一个错误时停止，返回 *RowError
+// nil Record 转换为零值（T 为指针时为 nil）
+func ToStructs[T any](records []*eorm.Record) ([]T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	isPtr := t.Kind() == reflect.Ptr
+	st := t
+	if isPtr {
+		st = t.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("batch: %s is not a struct or pointer to struct", t)
+	}
+	p := planFor(st)
+
+	out := make([]T, len(records))
+	for i, r := range records {
+		if r == nil {
+			continue
+		}
+		dst := reflect.ValueOf(&out[i]).Elem()
+		if isPtr {
+			dst.Set(reflect.New(st))
+			dst = dst.Elem()
+		}
+		if err := p.decode(r, dst); err != nil {
+			return nil, &RowError{Row: i, Err: err}
+		}
+	}
+	return out, nil
+}
+
+// FromStructs 将 []T 转换为 []*Record，T 可以是结构体或结构体指针，nil 指针转换为 nil
+// 列名规则与 eorm.FromStruct 一致，得到的 Record 可以直接用于 BatchInsertRecord
+func FromStructs[T any](items []T) ([]*eorm.Record, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	st := t
+	if t.Kind() == reflect.Ptr {
+		st = t.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("batch: %s is not a struct or pointer to struct", t)
+	}
+	p := planFor(st)
+
+	out := make([]*eorm.Record, len(items))
+	for i := range items {
+		v := reflect.ValueOf(&items[i]).Elem()
+		if t.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		out[i] = p.encode(v)
+	}
+	return out, nil
+}
+
+// asRecord 将 *Record、Record 值和 map[string]interface{} 统一转为 *Record，其他类型返回 nil
+func asRecord(v interface{}) *eorm.Record {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case *eorm.Record:
+		return val
+	case map[string]interface{}:
+		return eorm.FromMap(val)
+	}
+	if reflect.TypeOf(v) == recordType {
+		rec, _ := eorm.NewRecord().Set("v", v).GetRecord("v")
+		return rec
+	}
+	return nil
+}
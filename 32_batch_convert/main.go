@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+type User struct {
+	ID        int64     `column:"id"`
+	Name      string    `column:"name"`
+	Age       int       `column:"age"`
+	Email     *string   `column:"email"`
+	Score     float64   `column:"score"`
+	Active    bool      `column:"active"`
+	CreatedAt time.Time `column:"created_at"`
+	Profile   Profile   `column:"profile"`
+	Tags      []string  `column:"tags"`
+}
+
+type Profile struct {
+	City  string `column:"city"`
+	Level int    `column:"level"`
+}
+
+// Account 只包含平铺的列，用于和逐行 eorm.ToStruct 对比耗时
+type Account struct {
+	ID        int64     `column:"id"`
+	Name      string    `column:"name"`
+	Age       int       `column:"age"`
+	Email     string    `column:"email"`
+	Score     float64   `column:"score"`
+	Active    bool      `column:"active"`
+	CreatedAt time.Time `column:"created_at"`
+}
+
+// makeRows 模拟 eorm.Query 返回的结果
+func makeRows(n int) []*eorm.Record {
+	created := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	rows := make([]*eorm.Record, n)
+	for i := range rows {
+		rows[i] = eorm.NewRecord().
+			Set("id", int64(i+1)).
+			Set("name", fmt.Sprintf("user-%d", i+1)).
+			Set("age", int64(20+i%40)).
+			Set("email", fmt.Sprintf("user%d@example.com", i+1)).
+			Set("score", float64(i%100)+0.5).
+			Set("active", i%2 == 0).
+			Set("created_at", created.Add(time.Duration(i)*time.Minute)).
+			Set("profile", eorm.NewRecord().Set("city", "上海").Set("level", int64(i%5))).
+			Set("tags", []string{"a", "b"})
+	}
+	return rows
+}
+
+// 示例32：批量转换
+// 演示 ToStructs[T] 和 FromStructs 在 []*Record 与结构体切片之间转换，转换计划按类型缓存，错误信息包含行号
+func main() {
+	fmt.Println("========== 批量转换示例 ==========")
+
+	// 1. ToStructs
+	fmt.Println("\n1. ToStructs")
+	rows := makeRows(3)
+	users, err := ToStructs[User](rows)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return
+	}
+	for _, u := range users {
+		fmt.Printf("   ID=%d Name=%s Age=%d Email=%s City=%s Tags=%v\n", u.ID, u.Name, u.Age, *u.Email, u.Profile.City, u.Tags)
+	}
+	fmt.Println(`   rows, _ := eorm.Query("SELECT * FROM users WHERE active = ?", true)
+   users, err := ToStructs[User](rows)`)
+
+	// 2. 指针元素
+	fmt.Println("\n2. 指针元素")
+	ptrs, _ := ToStructs[*User](append(rows[:1:1], nil))
+	fmt.Printf("   len=%d ptrs[0].Name=%s ptrs[1]=%v（nil Record）\n", len(ptrs), ptrs[0].Name, ptrs[1])
+
+	// 3. FromStructs
+	fmt.Println("\n3. FromStructs")
+	records, _ := FromStructs(users[:2])
+	for _, r := range records {
+		fmt.Printf("   %s\n", r.ToJson())
+	}
+	fmt.Println(`   _, err = eorm.BatchInsertRecord("users", records)`)
+
+	// 4. 转换计划只生成一次
+	fmt.Println("\n4. 转换计划只生成一次")
+	const n = 100000
+	big := makeRows(n)
+	before := PlansBuilt()
+	start := time.Now()
+	accounts, err := ToStructs[Account](big)
+	batchCost := time.Since(start)
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return
+	}
+	fmt.Printf("   第一次转换 %d 行，新生成的计划: %d\n", len(accounts), PlansBuilt()-before)
+	before = PlansBuilt()
+	_, _ = ToStructs[*Account](big[:10])
+	_, _ = FromStructs(accounts[:10])
+	fmt.Printf("   再次转换（含指针元素和 FromStructs），新生成的计划: %d\n", PlansBuilt()-before)
+
+	start = time.Now()
+	loop := make([]Account, n)
+	for i, r := range big {
+		if err := eorm.ToStruct(r, &loop[i]); err != nil {
+			fmt.Printf("   ❌ 第 %d 行: %v\n", i, err)
+			return
+		}
+	}
+	loopCost := time.Since(start)
+	same := loop[n-1].Name == accounts[n-1].Name && loop[n-1].CreatedAt.Equal(accounts[n-1].CreatedAt)
+	fmt.Printf("   结果一致: %v\n", same)
+	fmt.Printf("   ToStructs: %v，逐行 eorm.ToStruct: %v（耗时随机器不同）\n", batchCost.Round(time.Millisecond), loopCost.Round(time.Millisecond))
+
+	// 5. 错误包含行号
+	fmt.Println("\n5. 错误包含行号")
+	bad := makeRows(5)
+	bad[3].Set("age", "unknown")
+	_, err = ToStructs[User](bad)
+	var rowErr *RowError
+	if errors.As(err, &rowErr) {
+		fmt.Printf("   (预期): %v\n", err)
+		fmt.Printf("   出错的行: %d，对应记录 id=%d\n", rowErr.Row, bad[rowErr.Row].GetInt64("id"))
+	}
+	bad[3].Set("age", 30).Set("profile", eorm.NewRecord().Set("city", "北京").Set("level", "high"))
+	if _, err := ToStructs[User](bad); err != nil {
+		fmt.Printf("   嵌套路径 (预期): %v\n", err)
+	}
+	if _, err := ToStructs[int](bad); err != nil {
+		fmt.Printf("   非结构体 (预期): %v\n", err)
+	}
+	type Metric struct {
+		Value float32 `column:"value"`
+	}
+	if _, err := ToStructs[Metric]([]*eorm.Record{eorm.NewRecord().Set("value", 1e300)}); err != nil {
+		fmt.Printf("   float32 溢出 (预期): %v\n", err)
+	}
+
+	// 6. 切片字段独立于 Record
+	fmt.Println("\n6. 切片字段独立于 Record")
+	copied, _ := ToStructs[User](rows[:1])
+	copied[0].Tags[0] = "changed"
+	fmt.Printf("   struct Tags=%v，Record tags=%v\n", copied[0].Tags, rows[0].Get("tags"))
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+type OrderCreated struct {
+	OrderID   int64     `json:"order_id"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrderPaid struct {
+	OrderID int64  `json:"order_id"`
+	Channel string `json:"channel"`
+}
+
+// OrderShipped 自身也有 Type 字段，列名的大小写与鉴别字段不同，解码时同样会得到鉴别值
+type OrderShipped struct {
+	Type       string `json:"Type"`
+	OrderID    int64  `json:"order_id"`
+	Carrier    string `json:"carrier"`
+	TrackingNo string `json:"tracking_no"`
+}
+
+// 用户事件使用独立的注册表和 kind 鉴别字段
+type UserSignedUp struct {
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+type UserDeleted struct {
+	UserID int64  `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+func init() {
+	RegisterType[OrderCreated](DefaultTypes, "order.created")
+	RegisterType[OrderPaid](DefaultTypes, "order.paid")
+	RegisterType[*OrderShipped](DefaultTypes, "order.shipped")
+}
+
+// 示例33：按鉴别字段解码
+// 演示事件流中按 type 字段将 Record 转换为不同的结构体，以及 FromStruct 时自动写入鉴别字段
+func main() {
+	fmt.Println("========== 按鉴别字段解码示例 ==========")
+
+	stream := []*eorm.Record{
+		eorm.NewRecord().Set("type", "order.created").Set("order_id", 1001).Set("amount", 299.5).Set("created_at", "2024-05-01T10:30:00Z"),
+		eorm.NewRecord().Set("type", "order.paid").Set("order_id", 1001).Set("channel", "alipay"),
+		eorm.NewRecord().Set("type", "order.shipped").Set("order_id", 1001).Set("carrier", "SF").Set("tracking_no", "SF123456"),
+	}
+
+	// 1. 逐条解码
+	fmt.Println("\n1. DecodeByDiscriminator")
+	for _, r := range stream {
+		v, err := DecodeByDiscriminator(r, "type")
+		if err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			continue
+		}
+		fmt.Printf("   %-14s -> %T\n", r.GetString("type"), v)
+	}
+
+	// 2. 按具体类型处理
+	fmt.Println("\n2. 按具体类型处理")
+	events, err := DecodeAll(stream, "type")
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		return
+	}
+	for _, e := range events {
+		switch ev := e.(type) {
+		case *OrderCreated:
+			fmt.Printf("   订单 %d 创建，金额 %.2f，时间 %s\n", ev.OrderID, ev.Amount, ev.CreatedAt.Format(time.RFC3339))
+		case *OrderPaid:
+			fmt.Printf("   订单 %d 已支付，渠道 %s\n", ev.OrderID, ev.Channel)
+		case *OrderShipped:
+			fmt.Printf("   订单 %d 已发货，%s %s（Type=%s）\n", ev.OrderID, ev.Carrier, ev.TrackingNo, ev.Type)
+		}
+	}
+
+	// 3. FromStruct 写入鉴别字段
+	fmt.Println("\n3. FromStruct 写入鉴别字段")
+	paid, _ := FromStruct(OrderPaid{OrderID: 1002, Channel: "wechat"}, "type")
+	fmt.Printf("   %s\n", paid.ToJson())
+	shipped, _ := FromStruct(&OrderShipped{Type: "旧值", OrderID: 1002, Carrier: "EMS"}, "type")
+	fmt.Printf("   %s\n", shipped.ToJson())
+	fmt.Println("   鉴别值总是第一列，结构体中的同名字段（Type，不区分大小写）被覆盖")
+
+	// 4. 往返
+	fmt.Println("\n4. 往返")
+	back, _ := DecodeByDiscriminator(paid, "type")
+	fmt.Printf("   %T %+v\n", back, *back.(*OrderPaid))
+
+	// 5. 独立的注册表和鉴别字段
+	fmt.Println("\n5. 独立的注册表")
+	userEvents := NewTypeRegistry()
+	RegisterType[UserSignedUp](userEvents, "signed_up")
+	RegisterType[UserDeleted](userEvents, "deleted")
+	row := eorm.NewRecord().Set("kind", "deleted").Set("user_id", 7).Set("reason", "注销")
+	if v, err := userEvents.Decode(row, "kind"); err == nil {
+		fmt.Printf("   kind=deleted -> %T %+v\n", v, *v.(*UserDeleted))
+	}
+	signed, _ := userEvents.FromStruct(UserSignedUp{UserID: 8, Email: "a@example.com"}, "kind")
+	fmt.Printf("   %s\n", signed.ToJson())
+	if _, err := DecodeByDiscriminator(row, "kind"); err != nil {
+		fmt.Printf("   DefaultTypes 中没有 deleted (预期): %v\n", err)
+	}
+
+	// 6. 错误处理
+	fmt.Println("\n6. 错误处理")
+	unknown := eorm.NewRecord().Set("type", "order.refunded").Set("order_id", 1001)
+	if _, err := DecodeByDiscriminator(unknown, "type"); errors.Is(err, ErrUnknownType) {
+		fmt.Printf("   未注册的类型 (预期): %v\n", err)
+	}
+	if _, err := DecodeByDiscriminator(eorm.NewRecord().Set("order_id", 1), "type"); errors.Is(err, ErrMissingDiscriminator) {
+		fmt.Printf("   缺少鉴别字段 (预期): %v\n", err)
+	}
+	if _, err := DecodeAll(append(stream, unknown), "type"); err != nil {
+		fmt.Printf("   DecodeAll (预期): %v\n", err)
+	}
+	if _, err := FromStruct(UserSignedUp{}, "type"); err != nil {
+		fmt.Printf("   FromStruct 未注册的类型 (预期): %v\n", err)
+	}
+
+	// 7. 跳过未知事件
+	fmt.Println("\n7. 跳过未知事件")
+	handled := 0
+	for _, r := range append(stream, unknown) {
+		if _, err := DecodeByDiscriminator(r, "type"); err != nil {
+			if errors.Is(err, ErrUnknownType) {
+				fmt.Printf("   跳过: %s\n", r.GetString("type"))
+				continue
+			}
+			fmt.Printf("   ❌ %v\n", err)
+			return
+		}
+		handled++
+	}
+	fmt.Printf("   已处理 %d 条\n", handled)
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/zzguang83325/eorm"
+)
+
+var (
+	// ErrMissingDiscriminator 表示 Record 中没有鉴别字段或字段为空
+	ErrMissingDiscriminator = errors.New("discriminator: missing discriminator")
+	// ErrUnknownType 表示鉴别值没有注册对应的类型
+	ErrUnknownType = errors.New("discriminator: unknown type")
+)
+
+// TypeRegistry 保存鉴别值与结构体类型的对应关系，可以并发使用
+// 鉴别值区分大小写，一个类型对应一个鉴别值
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// DefaultTypes 是全局 TypeRegistry，包级函数 DecodeByDiscriminator、FromStruct 等使用它
+var DefaultTypes = NewTypeRegistry()
+
+// NewTypeRegistry 创建空的 TypeRegistry
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{
+		types: make(map[string]reflect.Type),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// RegisterType 将鉴别值 name 对应到 T，T 可以是结构体或结构体指针
+// 重复注册同一个鉴别值或同一个类型时覆盖之前的对应关系；T 不是结构体时 panic，与 regexp.MustCompile 一致
+//
+// 示例：
+//
+//	RegisterType[OrderCreated](DefaultTypes, "order.created")
+func RegisterType[T any](reg *TypeRegistry, name string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("discriminator: %s is not a struct", t))
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if old, ok := reg.types[name]; ok {
+		delete(reg.names, old)
+	}
+	if old, ok := reg.names[t]; ok {
+		delete(reg.types, old)
+	}
+	reg.types[name] = t
+	reg.names[t] = name
+}
+
+// Lookup 返回鉴别值对应的类型
+func (reg *TypeRegistry) Lookup(name string) (reflect.Type, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	t, ok := reg.types[name]
+	return t, ok
+}
+
+// NameOf 返回 v 的类型注册的鉴别值，v 可以是结构体或结构体指针
+func (reg *TypeRegistry) NameOf(v interface{}) (string, bool) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "", false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	name, ok := reg.names[t]
+	return name, ok
+}
+
+// Decode 读取 r 中 field 列的鉴别值，通过 eorm.ToStruct 转换为注册的类型，返回指向结构体的指针
+// 没有鉴别值时返回 ErrMissingDiscriminator，鉴别值未注册时返回 ErrUnknownType，可以用 errors.Is 判断
+func (reg *TypeRegistry) Decode(r *eorm.Record, field string) (interface{}, error) {
+	if r == nil {
+		return nil, fmt.Errorf("discriminator: record is nil")
+	}
+	name := r.GetString(field)
+	if name == "" {
+		return nil, fmt.Errorf("%w: column '%s'", ErrMissingDiscriminator, field)
+	}
+	t, ok := reg.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s='%s'", ErrUnknownType, field, name)
+	}
+	ptr := reflect.New(t)
+	if err := eorm.ToStruct(r, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("discriminator: decode '%s' as %s: %v", name, t, err)
+	}
+	return ptr.Interface(), nil
+}
+
+// DecodeAll 逐行调用 Decode，遇到第一个错误时停止，错误信息包含行号
+func (reg *TypeRegistry) DecodeAll(records []*eorm.Record, field string) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(records))
+	for i, r := range records {
+		v, err := reg.Decode(r, field)
+		if err != nil {
+			return out, fmt.Errorf("row %d: %w", i, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// FromStruct 通过 eorm.FromStruct 将 v 转换为 Record，并把 v 的类型注册的鉴别值写入 field 列
+// 鉴别值总是第一列；结构体中映射到同名列的字段（不区分大小写，与 Record 一致）会被鉴别值覆盖；v 的类型未注册时返回 ErrUnknownType
+func (reg *TypeRegistry) FromStruct(v interface{}, field string) (*eorm.Record, error) {
+	name, ok := reg.NameOf(v)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not registered", ErrUnknownType, v)
+	}
+	fields := eorm.NewRecord()
+	if err := eorm.FromStruct(v, fields); err != nil {
+		return nil, fmt.Errorf("discriminator: %v", err)
+	}
+	out := eorm.NewRecord().Set(field, name)
+	for _, key := range fields.Keys() {
+		if strings.EqualFold(key, field) {
+			continue
+		}
+		out.Set(key, fields.Get(key))
+	}
+	return out, nil
+}
+
+// DecodeByDiscriminator 使用 DefaultTypes 将 r 转换为 field 列鉴别值对应的结构体
+func DecodeByDiscriminator(r *eorm.Record, field string) (interface{}, error) {
+	return DefaultTypes.Decode(r, field)
+}
+
+// DecodeAll 使用 DefaultTypes 逐行转换
+func DecodeAll(records []*eorm.Record, field string) ([]interface{}, error) {
+	return DefaultTypes.DecodeAll(records, field)
+}
+
+// FromStruct 使用 DefaultTypes 将 v 转换为 Record 并写入鉴别值
+func FromStruct(v interface{}, field string) (*eorm.Record, error) {
+	return DefaultTypes.FromStruct(v, field)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// loadUser 模拟 QueryFirst 返回的行，部分驱动以 []byte 返回字符串列
+func loadUser() *eorm.Record {
+	return eorm.NewRecord().
+		Set("id", int64(1)).
+		Set("name", []byte("张三")).
+		Set("age", int64(25)).
+		Set("email", "zhangsan@example.com").
+		Set("balance", 100.5).
+		Set("updated_at", time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC))
+}
+
+// updateSQL 按 Record 的列顺序拼出 UPDATE 语句，只用于展示
+func updateSQL(table string, set *eorm.Record, whereSQL string) string {
+	cols := make([]string, 0, len(set.Keys()))
+	for _, key := range set.Keys() {
+		cols = append(cols, key+" = ?")
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(cols, ", "), whereSQL)
+}
+
+// 示例34：修改跟踪
+// 演示记录 Record 加载时的原始值，找出修改过的列，UpdateRecord 只更新这些列，避免覆盖其他请求写入的值
+func main() {
+	fmt.Println("========== 修改跟踪示例 ==========")
+
+	// 1. 加载时记录原始值
+	fmt.Println("\n1. 加载时记录原始值")
+	user := Track(loadUser())
+	fmt.Println(`   user, _ := QueryFirst("SELECT * FROM users WHERE id = ?", 1)`)
+	fmt.Printf("   Changed()=%v\n", user.Changed())
+
+	// 2. 按使用指南中 UpdateUser 的方式合并更新
+	fmt.Println("\n2. 合并请求中的更新")
+	updateData := eorm.NewRecord().FromJson(`{"name": "张三", "age": 25, "email": "new@example.com"}`)
+	user.FromRecord(updateData)
+	user.Set("updated_at", time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC))
+	fmt.Printf("   Changed()=%v\n", user.Changed())
+	fmt.Println("   name 原值为 []byte，age 原值为 int64(25)，JSON 中的值与之相同，不算修改")
+	fmt.Printf("   FromRecord 替换全部列，合并后 id、balance 已不存在: Has(\"id\")=%v，但不算修改\n", user.Has("id"))
+
+	// 3. IsDirty 和 OriginalValue
+	fmt.Println("\n3. IsDirty 和 OriginalValue")
+	for _, key := range []string{"email", "age", "EMAIL"} {
+		fmt.Printf("   IsDirty(%q)=%v\n", key, user.IsDirty(key))
+	}
+	fmt.Printf("   email: %v -> %v\n", user.OriginalValue("email"), user.GetString("email"))
+
+	// 4. 只更新修改过的列
+	fmt.Println("\n4. 只更新修改过的列")
+	fmt.Println("   eorm.UpdateRecord: 合并后没有 id 列，返回 primary key 'id' not found；补上 id 后也会写入全部列")
+	fmt.Printf("                      %s\n", updateSQL("users", user.Record, "id = ?"))
+	changes, where, args, _ := user.UpdateArgs()
+	fmt.Printf("   UpdateRecord:      %s\n", updateSQL("users", changes, where))
+	fmt.Printf("   参数: %s %v\n", changes.ToJson(), args)
+	fmt.Println(`   rows, err := UpdateRecord("users", user)`)
+
+	// 5. 改回原值、置空、修改主键
+	fmt.Println("\n5. 改回原值、置空、修改主键")
+	user.Set("email", "zhangsan@example.com")
+	fmt.Printf("   email 改回原值后 IsDirty=%v\n", user.IsDirty("email"))
+	user.Set("balance", nil)
+	fmt.Printf("   Set(\"balance\", nil) 后 Changes()=%s\n", user.Changes().ToJson())
+	user.Set("id", int64(100))
+	changes, where, args, _ = user.UpdateArgs()
+	fmt.Printf("   修改主键: %s %v\n", updateSQL("users", changes, where), args)
+
+	// 6. ResetChanges
+	fmt.Println("\n6. ResetChanges")
+	user.ResetChanges()
+	fmt.Printf("   Changed()=%v OriginalValue(\"id\")=%v\n", user.Changed(), user.OriginalValue("id"))
+
+	// 7. 没有修改时不访问数据库
+	fmt.Println("\n7. 没有修改时不访问数据库")
+	rows, err := UpdateRecord("users", user)
+	fmt.Printf("   rows=%d err=%v\n", rows, err)
+
+	// 8. 复合主键
+	fmt.Println("\n8. 复合主键")
+	member := Track(eorm.NewRecord().Set("tenant_id", 7).Set("user_id", 1).Set("role", "viewer"), "tenant_id", "user_id")
+	member.Set("role", "admin")
+	changes, where, args, _ = member.UpdateArgs()
+	fmt.Printf("   %s %v\n", updateSQL("members", changes, where), args)
+
+	// 9. 错误处理
+	fmt.Println("\n9. 错误处理")
+	noKey := Track(eorm.NewRecord().Set("name", "李四"))
+	noKey.Set("name", "王五")
+	if _, err := UpdateRecord("users", noKey); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// DefaultPrimaryKeys 是 Track 未指定主键时使用的主键列
+var DefaultPrimaryKeys = []string{"id"}
+
+// Tracked 在 Record 之上记录加载时的原始值，用于找出修改过的列
+// 通过 Set、FromRecord 等任何方式修改的值都会被发现，因为比较的是当前值与原始值，而不是记录调用
+type Tracked struct {
+	*eorm.Record
+	original    *eorm.Record
+	primaryKeys []string
+}
+
+// Track 保存 r 当前的值作为原始值，primaryKeys 为空时使用 DefaultPrimaryKeys
+// 之后对 r 的修改通过返回的 Tracked 查看；r 为 nil 时返回 nil
+func Track(r *eorm.Record, primaryKeys ...string) *Tracked {
+	if r == nil {
+		return nil
+	}
+	if len(primaryKeys) == 0 {
+		primaryKeys = DefaultPrimaryKeys
+	}
+	return &Tracked{
+		Record:      r,
+		original:    r.DeepClone(),
+		primaryKeys: append([]string(nil), primaryKeys...),
+	}
+}
+
+// TrackAll 对每条 Record 调用 Track
+func TrackAll(records []*eorm.Record, primaryKeys ...string) []*Tracked {
+	out := make([]*Tracked, len(records))
+	for i, r := range records {
+		out[i] = Track(r, primaryKeys...)
+	}
+	return out
+}
+
+// Query 与 eorm.Query 相同，返回的 Record 记录原始值，主键使用 DefaultPrimaryKeys
+func Query(querySQL string, args ...interface{}) ([]*Tracked, error) {
+	records, err := eorm.Query(querySQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	return TrackAll(records), nil
+}
+
+// QueryFirst 与 eorm.QueryFirst 相同，返回的 Record 记录原始值，没有结果时返回 nil
+func QueryFirst(querySQL string, args ...interface{}) (*Tracked, error) {
+	record, err := eorm.QueryFirst(querySQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	return Track(record), nil
+}
+
+// Changed 按当前列顺序返回修改过的列，即新增的列和值与原始值不同的列
+// 被删除的列不算修改：FromRecord 会替换全部列，不能因此把未提交的列更新为 NULL；需要置空时使用 Set(key, nil)
+func (t *Tracked) Changed() []string {
+	var changed []string
+	for _, key := range t.Keys() {
+		if t.IsDirty(key) {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// IsDirty 判断 key 列是否被修改，列名不区分大小写
+// 值改回原始值后不再视为修改；数值按大小比较，因此 JSON 中的 25.0 与数据库中的 int64(25) 相同
+func (t *Tracked) IsDirty(key string) bool {
+	if !t.Has(key) {
+		return false
+	}
+	return !t.original.Has(key) || !sameValue(t.original.Get(key), t.Get(key))
+}
+
+// OriginalValue 返回 key 列加载时的值，加载时不存在的列返回 nil
+func (t *Tracked) OriginalValue(key string) interface{} {
+	return t.original.Get(key)
+}
+
+// ResetChanges 将当前值作为新的原始值，通常在保存成功后调用
+func (t *Tracked) ResetChanges() {
+	t.original = t.Record.DeepClone()
+}
+
+// Changes 返回只包含修改过的列的 Record
+func (t *Tracked) Changes() *eorm.Record {
+	changes := eorm.NewRecord()
+	for _, key := range t.Changed() {
+		changes.Set(key, t.Get(key))
+	}
+	return changes
+}
+
+// UpdateArgs 返回 UpdateRecord 使用的参数：修改过的列，以及按主键原始值生成的 WHERE 条件
+// 使用原始值定位行，因此修改主键列也可以正确更新
+func (t *Tracked) UpdateArgs() (changes *eorm.Record, whereSQL string, whereArgs []interface{}, err error) {
+	clauses := make([]string, 0, len(t.primaryKeys))
+	for _, pk := range t.primaryKeys {
+		v := t.original.Get(pk)
+		if v == nil {
+			return nil, "", nil, fmt.Errorf("tracking: primary key '%s' not found in original record", pk)
+		}
+		clauses = append(clauses, pk+" = ?")
+		whereArgs = append(whereArgs, v)
+	}
+	return t.Changes(), strings.Join(clauses, " AND "), whereArgs, nil
+}
+
+// UpdateRecord 只更新修改过的列，没有修改时不访问数据库并返回 0
+// 更新成功后调用 ResetChanges，之后的修改重新开始记录
+func UpdateRecord(table string, t *Tracked) (int64, error) {
+	if t == nil {
+		return 0, fmt.Errorf("tracking: record is nil")
+	}
+	changes, whereSQL, whereArgs, err := t.UpdateArgs()
+	if err != nil {
+		return 0, err
+	}
+	if changes.IsEmpty() {
+		return 0, nil
+	}
+	rows, err := eorm.Update(table, changes, whereSQL, whereArgs...)
+	if err != nil {
+		return 0, err
+	}
+	t.ResetChanges()
+	return rows, nil
+}
+
+// sameValue 判断两个值是否相同：
+// 1. reflect.DeepEqual 相同
+// 2. 两个 time.Time 表示同一时刻
+// 3. []byte 与 string 内容相同（部分驱动以 []byte 返回字符串列）
+// 4. 两个数值大小相同，不区分 int64、float64 等类型
+func sameValue(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	if sa, ok := textOf(a); ok {
+		sb, ok := textOf(b)
+		return ok && sa == sb
+	}
+	ra, okA := ratOf(a)
+	rb, okB := ratOf(b)
+	return okA && okB && ra.Cmp(rb) == 0
+}
+
+func textOf(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	}
+	return "", false
+}
+
+// ratOf 将整数和浮点数精确转换为 big.Rat，NaN 和 Inf 不视为数值
+func ratOf(v interface{}) (*big.Rat, bool) {
+	if v == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		r := new(big.Rat).SetFloat64(rv.Float())
+		return r, r != nil
+	}
+	return nil, false
+}
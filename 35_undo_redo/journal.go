@@ -0,0 +1,398 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// ErrUnrecorded 表示 Record 在 Journal 之外被修改过，此时撤销或重做会覆盖这些修改，因此拒绝执行
+var ErrUnrecorded = errors.New("journal: record was modified outside the journal")
+
+// change 是对某个 Record 中一列的一次修改
+type change struct {
+	path       []string // 从根 Record 到被修改 Record 的列名，根 Record 为空
+	key        string
+	existed    bool
+	old        interface{}
+	newExisted bool
+	new        interface{}
+	order      []string // 删除列时保存修改前的列顺序，撤销时恢复位置
+}
+
+// step 是一次 Set、Delete 或 FromMap 调用产生的修改，撤销和重做以 step 为单位
+type step struct {
+	seq     int
+	changes []change
+}
+
+// Journal 记录通过它对 Record 做的修改，支持撤销、重做和保存点，可以并发使用
+// 不使用 Journal 时 Record 没有任何额外开销；直接调用 Record 的方法所做的修改不会被记录，
+// Undo、Redo、RollbackTo 执行每个步骤前检查该步骤涉及的列是否仍是记录的值，不一致时返回 ErrUnrecorded
+type Journal struct {
+	mu   sync.Mutex
+	root *eorm.Record
+	undo []step
+	redo []step
+	seq  int
+	err  error
+	*Editor
+}
+
+// Editor 通过 Journal 修改根 Record 或其中的嵌套 Record，对应 Record 的 GetRecord 得到的句柄
+type Editor struct {
+	j    *Journal
+	path []string
+}
+
+// Savepoint 是 Journal 中的一个位置，RollbackTo 撤销它之后的全部修改
+type Savepoint struct {
+	depth int
+	seq   int
+}
+
+// NewJournal 开始记录对 r 的修改
+func NewJournal(r *eorm.Record) *Journal {
+	j := &Journal{root: r}
+	j.Editor = &Editor{j: j}
+	return j
+}
+
+// Record 返回被记录的根 Record，用于读取
+func (j *Journal) Record() *eorm.Record {
+	return j.root
+}
+
+// Err 返回第一个失败的修改的错误，例如嵌套 Record 的路径已不存在
+func (j *Journal) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Set 设置列值，作为一个可撤销的步骤
+func (e *Editor) Set(key string, value interface{}) *Editor {
+	e.j.record(e.path, map[string]interface{}{key: value}, nil)
+	return e
+}
+
+// Delete 删除列，作为一个可撤销的步骤；列不存在时不产生步骤
+func (e *Editor) Delete(key string) *Editor {
+	e.j.record(e.path, nil, []string{key})
+	return e
+}
+
+// FromMap 按键名排序后逐列设置，整体作为一个可撤销的步骤
+func (e *Editor) FromMap(m map[string]interface{}) *Editor {
+	e.j.record(e.path, m, nil)
+	return e
+}
+
+// GetRecord 返回嵌套 Record 的 Editor，通过它做的修改同样记录在 Journal 中
+// 列的值必须是 Record、*Record 或 map[string]interface{}
+func (e *Editor) GetRecord(key string) (*Editor, error) {
+	return e.GetRecordByPath(key)
+}
+
+// GetRecordByPath 按点分路径返回嵌套 Record 的 Editor，例如 "address.geo"
+func (e *Editor) GetRecordByPath(path string) (*Editor, error) {
+	full := append(append([]string(nil), e.path...), strings.Split(path, ".")...)
+	e.j.mu.Lock()
+	defer e.j.mu.Unlock()
+	if _, err := lookup(e.j.root, full); err != nil {
+		return nil, err
+	}
+	return &Editor{j: e.j, path: full}, nil
+}
+
+// Get 读取当前 Editor 对应的 Record 中的列值，路径已不存在时返回 nil
+func (e *Editor) Get(key string) interface{} {
+	e.j.mu.Lock()
+	defer e.j.mu.Unlock()
+	r, err := lookup(e.j.root, e.path)
+	if err != nil {
+		return nil
+	}
+	return r.Get(key)
+}
+
+// Savepoint 返回当前位置
+func (j *Journal) Savepoint() Savepoint {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.savepointLocked()
+}
+
+func (j *Journal) savepointLocked() Savepoint {
+	sp := Savepoint{depth: len(j.undo)}
+	if sp.depth > 0 {
+		sp.seq = j.undo[sp.depth-1].seq
+	}
+	return sp
+}
+
+// RollbackTo 撤销 sp 之后的全部修改，撤销的步骤可以用 Redo 恢复
+// sp 之前的步骤已被撤销并产生了新的修改时，sp 不再有效；出错时返回错误且不做任何修改
+func (j *Journal) RollbackTo(sp Savepoint) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if sp.depth > len(j.undo) || (sp.depth > 0 && j.undo[sp.depth-1].seq != sp.seq) {
+		return fmt.Errorf("journal: savepoint is no longer valid")
+	}
+	undone := 0
+	for len(j.undo) > sp.depth {
+		if err := j.undoLocked(); err != nil {
+			// 重做已撤销的步骤，恢复到回滚之前
+			for ; undone > 0; undone-- {
+				j.redoLocked()
+			}
+			return err
+		}
+		undone++
+	}
+	return nil
+}
+
+// Undo 撤销最近一个步骤，没有可撤销的步骤或 Record 在 Journal 之外被修改过时返回 false，后者记录到 Err
+func (j *Journal) Undo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.undo) == 0 || j.setErr(j.undoLocked()) != nil {
+		return false
+	}
+	return true
+}
+
+// Redo 重做最近一个被撤销的步骤，没有可重做的步骤时返回 false；新的修改会清空可重做的步骤
+// 与 Undo 一样，Record 在 Journal 之外被修改过时返回 false
+func (j *Journal) Redo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.redo) == 0 || j.setErr(check(j.root, j.redo[len(j.redo)-1], true)) != nil {
+		return false
+	}
+	if j.setErr(j.redoLocked()) != nil {
+		return false
+	}
+	return true
+}
+
+// CanUndo 判断是否有可撤销的步骤
+func (j *Journal) CanUndo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.undo) > 0
+}
+
+// CanRedo 判断是否有可重做的步骤
+func (j *Journal) CanRedo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.redo) > 0
+}
+
+func (j *Journal) undoLocked() error {
+	s := j.undo[len(j.undo)-1]
+	if err := check(j.root, s, false); err != nil {
+		return err
+	}
+	for i := len(s.changes) - 1; i >= 0; i-- {
+		if err := apply(j.root, s.changes[i], true); err != nil {
+			return err
+		}
+	}
+	j.undo = j.undo[:len(j.undo)-1]
+	j.redo = append(j.redo, s)
+	return nil
+}
+
+func (j *Journal) redoLocked() error {
+	s := j.redo[len(j.redo)-1]
+	for _, c := range s.changes {
+		if err := apply(j.root, c, false); err != nil {
+			return err
+		}
+	}
+	j.redo = j.redo[:len(j.redo)-1]
+	j.undo = append(j.undo, s)
+	return nil
+}
+
+// check 确认 s 涉及的列仍是 s 执行后（撤销前）或执行前（重做前）的值，发现 Journal 之外的修改
+// 只比较 s 涉及的列，开销与步骤大小成正比，与 Record 的大小无关
+func check(root *eorm.Record, s step, beforeRedo bool) error {
+	seen := make(map[string]bool, len(s.changes))
+	for i := range s.changes {
+		// 同一列在一个步骤中修改多次时，撤销前比较最后一次的新值，重做前比较第一次的旧值
+		c := s.changes[len(s.changes)-1-i]
+		exists, value := c.newExisted, c.new
+		if beforeRedo {
+			c = s.changes[i]
+			exists, value = c.existed, c.old
+		}
+		id := strings.ToLower(strings.Join(append(append([]string(nil), c.path...), c.key), "."))
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		r, err := lookup(root, c.path)
+		if err != nil || r.Has(c.key) != exists || (exists && !sameValue(r.Get(c.key), value)) {
+			return ErrUnrecorded
+		}
+	}
+	return nil
+}
+
+// sameValue 用 reflect.DeepEqual 比较，类型不同（如 int 与 float64）视为不同；
+// 嵌套 Record 无论以值、指针还是 map 保存，都展开后按列比较
+func sameValue(a, b interface{}) bool {
+	return reflect.DeepEqual(plain(a), plain(b))
+}
+
+// plain 把嵌套 Record 展开为小写键名的 map
+func plain(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *eorm.Record:
+		m := make(map[string]interface{}, len(val.Keys()))
+		for _, key := range val.Keys() {
+			m[strings.ToLower(key)] = plain(val.Get(key))
+		}
+		return m
+	case eorm.Record:
+		return plain(&val)
+	case map[string]interface{}:
+		return plain(eorm.FromMap(val))
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = plain(item)
+		}
+		return out
+	}
+	return v
+}
+
+func (j *Journal) setErr(err error) error {
+	if err != nil && j.err == nil {
+		j.err = err
+	}
+	return err
+}
+
+// record 对 path 对应的 Record 设置 sets 中的列、删除 deletes 中的列，并记录为一个步骤
+func (j *Journal) record(path []string, sets map[string]interface{}, deletes []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	keys := make([]string, 0, len(sets))
+	for k := range sets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []change
+	err := edit(j.root, path, func(r *eorm.Record) {
+		for _, k := range keys {
+			c := change{path: path, key: k, existed: r.Has(k), old: r.Get(k), newExisted: true}
+			r.Set(k, sets[k])
+			// 保存 Set 之后实际存入的值（指针已被解引用），检查和重做都使用它
+			c.new = r.Get(k)
+			changes = append(changes, c)
+		}
+		for _, k := range deletes {
+			if !r.Has(k) {
+				continue
+			}
+			changes = append(changes, change{path: path, key: k, existed: true, old: r.Get(k), order: r.Keys()})
+			r.Delete(k)
+		}
+	})
+	if j.setErr(err) != nil || len(changes) == 0 {
+		return
+	}
+	j.seq++
+	j.undo = append(j.undo, step{seq: j.seq, changes: changes})
+	j.redo = nil
+}
+
+// apply 撤销（reverse 为 true）或重做一次修改
+func apply(root *eorm.Record, c change, reverse bool) error {
+	exists, value := c.newExisted, c.new
+	if reverse {
+		exists, value = c.existed, c.old
+	}
+	return edit(root, c.path, func(r *eorm.Record) {
+		switch {
+		case !exists:
+			r.Delete(c.key)
+		case reverse && c.order != nil:
+			restoreAt(r, c.key, value, c.order)
+		default:
+			r.Set(c.key, value)
+		}
+	})
+}
+
+// restoreAt 恢复被删除的列，并按 order 把它放回原来的位置
+// Record 只能在末尾追加列，因此将原位置之后的列依次删除后重新追加
+func restoreAt(r *eorm.Record, key string, value interface{}, order []string) {
+	r.Set(key, value)
+	after := false
+	for _, k := range order {
+		if strings.EqualFold(k, key) {
+			after = true
+			continue
+		}
+		if after && r.Has(k) {
+			v := r.Get(k)
+			r.Delete(k)
+			r.Set(k, v)
+		}
+	}
+}
+
+// edit 找到 path 对应的 Record 并调用 fn
+// 嵌套 Record 以值或 map 保存时，GetRecord 得到的是副本，修改后写回上一层
+func edit(r *eorm.Record, path []string, fn func(r *eorm.Record)) error {
+	if len(path) == 0 {
+		fn(r)
+		return nil
+	}
+	key := path[0]
+	child, err := lookup(r, path[:1])
+	if err != nil {
+		return err
+	}
+	if err := edit(child, path[1:], fn); err != nil {
+		return err
+	}
+	if _, shared := r.Get(key).(*eorm.Record); !shared {
+		r.Set(key, child)
+	}
+	return nil
+}
+
+// lookup 按 path 逐层取出嵌套 Record，不修改 Record
+// 只接受 Record、*Record 和 map[string]interface{}，不把 JSON 字符串等其他值当作 Record
+func lookup(r *eorm.Record, path []string) (*eorm.Record, error) {
+	for i, key := range path {
+		switch raw := r.Get(key).(type) {
+		case nil:
+			return nil, fmt.Errorf("journal: '%s' not found", strings.Join(path[:i+1], "."))
+		case *eorm.Record, eorm.Record, map[string]interface{}:
+		default:
+			return nil, fmt.Errorf("journal: '%s' is %T, not a Record", strings.Join(path[:i+1], "."), raw)
+		}
+		child, err := r.GetRecord(key)
+		if err != nil {
+			return nil, fmt.Errorf("journal: %v", err)
+		}
+		r = child
+	}
+	return r, nil
+}
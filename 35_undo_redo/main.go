@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 示例35：撤销、重做和保存点
+// 演示通过 Journal 修改 Record，按步骤撤销和重做，回滚到保存点，以及记录嵌套 Record 的修改
+func main() {
+	fmt.Println("========== 撤销、重做和保存点示例 ==========")
+
+	// address 由 FromJson 生成，以 *Record 保存；profile 由 Set 保存为 Record 值
+	form := eorm.NewRecord().
+		FromJson(`{"address": {"city": "上海"}}`).
+		Set("id", 1).
+		Set("name", "张三").
+		Set("profile", eorm.NewRecord().Set("nickname", "zs").Set("prefs", eorm.NewRecord().Set("theme", "light"))).
+		Set("tmp_token", "abc")
+	if addr, err := form.GetRecord("address"); err == nil {
+		addr.Set("street", "南京路")
+	}
+	j := NewJournal(form)
+	fmt.Printf("\n初始: %s\n", form.ToJson())
+
+	// 1. 撤销和重做
+	fmt.Println("\n1. 撤销和重做")
+	j.Set("name", "李四").Set("email", "lisi@example.com")
+	fmt.Printf("   修改后: name=%s email=%v\n", form.GetString("name"), form.Get("email"))
+	j.Undo()
+	fmt.Printf("   Undo:   name=%s email=%v\n", form.GetString("name"), form.Get("email"))
+	j.Undo()
+	fmt.Printf("   Undo:   name=%s email=%v\n", form.GetString("name"), form.Get("email"))
+	j.Redo()
+	fmt.Printf("   Redo:   name=%s email=%v\n", form.GetString("name"), form.Get("email"))
+	fmt.Printf("   CanUndo=%v CanRedo=%v\n", j.CanUndo(), j.CanRedo())
+
+	// 2. 保存点
+	fmt.Println("\n2. 回滚到保存点")
+	sp := j.Savepoint()
+	before := form.ToJson()
+	j.Delete("tmp_token")
+	j.FromMap(map[string]interface{}{"age": 30, "name": "王五"})
+	address, _ := j.GetRecord("address")
+	address.Set("city", "北京").Delete("street")
+	prefs, _ := j.GetRecordByPath("profile.prefs")
+	prefs.Set("theme", "dark")
+	fmt.Printf("   修改后: %s\n", form.ToJson())
+	if err := j.RollbackTo(sp); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	fmt.Printf("   回滚后: %s\n", form.ToJson())
+	fmt.Printf("   与保存点一致（含列顺序）: %v\n", form.ToJson() == before)
+
+	// 3. 回滚的步骤可以重做
+	fmt.Println("\n3. 重做回滚的步骤")
+	for j.CanRedo() {
+		j.Redo()
+	}
+	fmt.Printf("   address=%s theme=%v\n", form.Get("address"), prefs.Get("theme"))
+
+	// 4. 新的修改清空重做，失效的保存点
+	fmt.Println("\n4. 失效的保存点")
+	sp2 := j.Savepoint()
+	j.Set("step", 2)
+	j.Undo()
+	j.Undo()
+	j.Set("step", 3)
+	fmt.Printf("   CanRedo=%v\n", j.CanRedo())
+	if err := j.RollbackTo(sp2); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+
+	// 5. 数据清洗流程：一批规则失败时回到开始
+	fmt.Println("\n5. 数据清洗流程")
+	row := eorm.NewRecord().Set("phone", " 138-0000-0000 ").Set("email", "A@Example.COM")
+	cleaner := NewJournal(row)
+	start := cleaner.Savepoint()
+	cleaner.Set("phone", "13800000000")
+	cleaner.Set("email", "a@example.com")
+	if age := row.Get("age"); age == nil {
+		fmt.Println("   缺少 age，放弃本行的全部清洗")
+		_ = cleaner.RollbackTo(start)
+	}
+	fmt.Printf("   %s\n", row.ToJson())
+
+	// 6. 错误处理
+	fmt.Println("\n6. 错误处理")
+	if _, err := j.GetRecord("name"); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	j.Delete("address")
+	address.Set("city", "广州")
+	fmt.Printf("   address 已删除后通过旧句柄修改 (预期): %v\n", j.Err())
+	sp = j.Savepoint()
+	j.Set("score", 90).Set("name", "赵六")
+	form.Set("score", 90.0)
+	if err := j.RollbackTo(sp); errors.Is(err, ErrUnrecorded) {
+		fmt.Printf("   直接调用 form.Set 后回滚 (预期): %v\n", err)
+	}
+	fmt.Printf("   Record 保持不变: name=%v score=%v (%T)\n", form.Get("name"), form.Get("score"), form.Get("score"))
+	form.Set("id", 2)
+	fmt.Printf("   未涉及的列 id 被直接修改，Undo=%v: name=%v id=%v\n", j.Undo(), form.Get("name"), form.Get("id"))
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
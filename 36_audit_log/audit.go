@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 审计日志的操作类型
+const (
+	OpSet    = "set"
+	OpDelete = "delete"
+	OpClear  = "clear"
+)
+
+// Entry 是一条审计记录：谁在什么时间把哪一列从什么值改成了什么值
+type Entry struct {
+	Path  string      `json:"path"` // 列路径，嵌套 Record 用点分隔，例如 address.city
+	Op    string      `json:"op"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+	Actor string      `json:"actor"`
+	Time  time.Time   `json:"time"`
+}
+
+// ToRecord 将 Entry 转换为可以写入审计表的 Record
+// old_value、new_value 保存为 JSON 文本，值为 nil 时为 NULL
+func (e Entry) ToRecord() *eorm.Record {
+	return eorm.NewRecord().
+		Set("path", e.Path).
+		Set("op", e.Op).
+		Set("old_value", jsonText(e.Old)).
+		Set("new_value", jsonText(e.New)).
+		Set("actor", e.Actor).
+		Set("changed_at", e.Time)
+}
+
+// ToRecords 将多条 Entry 转换为 Record，结果可以直接传给 eorm.BatchInsertRecord
+func ToRecords(entries []Entry) []*eorm.Record {
+	records := make([]*eorm.Record, len(entries))
+	for i, e := range entries {
+		records[i] = e.ToRecord()
+	}
+	return records
+}
+
+// WriteNDJSON 将 Entry 逐行写为 JSON（NDJSON 格式）
+func WriteNDJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("audit: %v", err)
+		}
+	}
+	return nil
+}
+
+func jsonText(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Sink 接收审计记录，可以写入内存、文件、消息队列或数据库
+type Sink interface {
+	Write(e Entry) error
+}
+
+// SinkFunc 将函数用作 Sink
+type SinkFunc func(e Entry) error
+
+func (f SinkFunc) Write(e Entry) error {
+	return f(e)
+}
+
+// MemorySink 在内存中保存审计记录，可以并发使用
+type MemorySink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (s *MemorySink) Write(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Entries 返回已保存的审计记录的副本
+func (s *MemorySink) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// NDJSONSink 将每条审计记录立即写为一行 JSON，可以并发使用
+type NDJSONSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewNDJSONSink 创建写入 w 的 NDJSONSink
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &NDJSONSink{enc: enc}
+}
+
+func (s *NDJSONSink) Write(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(e)
+}
+
+// MultiSink 依次写入多个 Sink，返回第一个错误
+func MultiSink(sinks ...Sink) Sink {
+	return SinkFunc(func(e Entry) error {
+		for _, s := range sinks {
+			if err := s.Write(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type actorKey struct{}
+
+// WithActor 返回携带操作人的 context，通常在请求的中间件中设置
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 返回 context 中的操作人，没有时返回空字符串
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// Auditor 保存审计配置
+type Auditor struct {
+	// Sink 接收审计记录，不能为 nil
+	Sink Sink
+	// Now 返回记录的时间，为 nil 时使用 time.Now
+	Now func() time.Time
+	// DefaultActor 在 context 中没有操作人时使用，例如 "system"
+	DefaultActor string
+}
+
+// Audit 返回记录审计日志的 Audited，操作人从 ctx 中读取
+// 只有通过 Audited 的修改会被记录，不使用审计时 Record 没有任何额外开销
+func (a *Auditor) Audit(ctx context.Context, r *eorm.Record) *Audited {
+	actor := ActorFrom(ctx)
+	if actor == "" {
+		actor = a.DefaultActor
+	}
+	return &Audited{state: &auditState{auditor: a, actor: actor, root: r}}
+}
+
+// Audited 通过审计修改 Record 或其中的嵌套 Record
+type Audited struct {
+	state *auditState
+	path  []string
+}
+
+type auditState struct {
+	mu      sync.Mutex
+	auditor *Auditor
+	actor   string
+	root    *eorm.Record
+	err     error
+}
+
+// Record 返回被审计的根 Record，用于读取
+func (a *Audited) Record() *eorm.Record {
+	return a.state.root
+}
+
+// Actor 返回本次审计的操作人
+func (a *Audited) Actor() string {
+	return a.state.actor
+}
+
+// Err 返回第一个错误，例如 Sink 写入失败或嵌套 Record 的路径已不存在
+// Sink 写入失败时修改仍然生效，调用方应检查 Err 并决定是否放弃保存
+func (a *Audited) Err() error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.state.err
+}
+
+// Set 设置列值并记录审计日志，值没有变化时不记录
+func (a *Audited) Set(key string, value interface{}) *Audited {
+	a.modify(func(r *eorm.Record, log func(key, op string, old, new interface{})) {
+		log(key, OpSet, r.Get(key), value)
+		r.Set(key, value)
+	})
+	return a
+}
+
+// Delete 删除列并记录审计日志，列不存在时不记录
+func (a *Audited) Delete(key string) *Audited {
+	a.modify(func(r *eorm.Record, log func(key, op string, old, new interface{})) {
+		if !r.Has(key) {
+			return
+		}
+		log(key, OpDelete, r.Get(key), nil)
+		r.Delete(key)
+	})
+	return a
+}
+
+// Clear 删除全部列，每一列记录一条审计日志
+func (a *Audited) Clear() *Audited {
+	a.modify(func(r *eorm.Record, log func(key, op string, old, new interface{})) {
+		for _, key := range r.Keys() {
+			log(key, OpClear, r.Get(key), nil)
+		}
+		r.Clear()
+	})
+	return a
+}
+
+// FromMap 按键名排序后逐列设置，每个值有变化的列记录一条审计日志
+func (a *Audited) FromMap(m map[string]interface{}) *Audited {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	a.modify(func(r *eorm.Record, log func(key, op string, old, new interface{})) {
+		for _, key := range keys {
+			log(key, OpSet, r.Get(key), m[key])
+			r.Set(key, m[key])
+		}
+	})
+	return a
+}
+
+// Merge 将 src 的列合并到 Record，src 中没有的列保持不变，每个值有变化的列记录一条审计日志
+// 与 eorm 的 FromRecord 不同，FromRecord 会替换全部列
+func (a *Audited) Merge(src *eorm.Record) *Audited {
+	if src == nil {
+		return a
+	}
+	a.modify(func(r *eorm.Record, log func(key, op string, old, new interface{})) {
+		for _, key := range src.Keys() {
+			log(key, OpSet, r.Get(key), src.Get(key))
+			r.Set(key, src.Get(key))
+		}
+	})
+	return a
+}
+
+// GetRecord 返回嵌套 Record 的 Audited，审计日志中的路径带上列名前缀
+func (a *Audited) GetRecord(key string) (*Audited, error) {
+	path := append(append([]string(nil), a.path...), strings.Split(key, ".")...)
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if _, err := lookup(a.state.root, path); err != nil {
+		return nil, err
+	}
+	return &Audited{state: a.state, path: path}, nil
+}
+
+// modify 在 a 对应的 Record 上执行 fn，fn 通过 log 记录每一列的修改
+func (a *Audited) modify(fn func(r *eorm.Record, log func(key, op string, old, new interface{}))) {
+	s := a.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now
+	if s.auditor.Now != nil {
+		now = s.auditor.Now
+	}
+	prefix := strings.Join(a.path, ".")
+	err := edit(s.root, a.path, func(r *eorm.Record) {
+		fn(r, func(key, op string, old, new interface{}) {
+			if op == OpSet && r.Has(key) && sameValue(old, new) {
+				// 值没有变化，不记录
+				return
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			e := Entry{Path: path, Op: op, Old: snapshot(old), New: snapshot(new), Actor: s.actor, Time: now()}
+			if err := s.auditor.Sink.Write(e); err != nil && s.err == nil {
+				s.err = fmt.Errorf("audit: write %s: %v", path, err)
+			}
+		})
+	})
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// sameValue 用 reflect.DeepEqual 比较，类型不同（如 int64(1) 与 float64(1)）视为修改；
+// 嵌套 Record 无论以值、指针还是 map 保存，都先展开为快照再比较，列的顺序和大小写不影响结果
+func sameValue(a, b interface{}) bool {
+	return reflect.DeepEqual(plain(a), plain(b))
+}
+
+// plain 把嵌套 Record 展开为小写键名的 map 快照，使 DeepEqual 只比较列和值
+func plain(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *eorm.Record:
+		m := make(map[string]interface{}, len(val.Keys()))
+		for _, key := range val.Keys() {
+			m[strings.ToLower(key)] = plain(val.Get(key))
+		}
+		return m
+	case eorm.Record:
+		return plain(&val)
+	case map[string]interface{}:
+		return plain(eorm.FromMap(val))
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = plain(item)
+		}
+		return out
+	}
+	return v
+}
+
+// snapshot 复制嵌套 Record，避免之后的修改改变已记录的值；Record 值统一转为 *Record，以便序列化为 JSON
+func snapshot(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *eorm.Record:
+		return val.DeepClone()
+	case eorm.Record:
+		return (&val).DeepClone()
+	case map[string]interface{}:
+		return eorm.FromMap(val).DeepClone()
+	}
+	return v
+}
+
+// edit 在 path 对应的嵌套 Record 上执行 fn，规则与示例 35 的 edit 相同
+func edit(r *eorm.Record, path []string, fn func(r *eorm.Record)) error {
+	if len(path) == 0 {
+		fn(r)
+		return nil
+	}
+	key := path[0]
+	child, err := lookup(r, path[:1])
+	if err != nil {
+		return err
+	}
+	if err := edit(child, path[1:], fn); err != nil {
+		return err
+	}
+	if _, shared := r.Get(key).(*eorm.Record); !shared {
+		r.Set(key, child)
+	}
+	return nil
+}
+
+// lookup 逐层取出嵌套 Record，路径不存在或不是 Record 时返回错误
+func lookup(r *eorm.Record, path []string) (*eorm.Record, error) {
+	for i, key := range path {
+		switch raw := r.Get(key).(type) {
+		case nil:
+			return nil, fmt.Errorf("audit: '%s' not found", strings.Join(path[:i+1], "."))
+		case *eorm.Record, eorm.Record, map[string]interface{}:
+		default:
+			return nil, fmt.Errorf("audit: '%s' is %T, not a Record", strings.Join(path[:i+1], "."), raw)
+		}
+		child, err := r.GetRecord(key)
+		if err != nil {
+			return nil, fmt.Errorf("audit: %v", err)
+		}
+		r = child
+	}
+	return r, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// fakeClock 每次调用前进一秒，让示例输出固定
+func fakeClock() func() time.Time {
+	t := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+func printIndented(buf *bytes.Buffer) {
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+}
+
+// 示例36：审计日志
+// 演示记录每次修改的列路径、旧值、新值、操作人和时间，操作人从 context 中读取，导出为 NDJSON 和可以批量插入的 Record
+func main() {
+	fmt.Println("========== 审计日志示例 ==========")
+
+	sink := &MemorySink{}
+	auditor := &Auditor{Sink: sink, Now: fakeClock(), DefaultActor: "system"}
+
+	user := eorm.NewRecord().
+		Set("id", 1).
+		Set("name", "张三").
+		Set("email", "zhangsan@example.com").
+		Set("address", eorm.NewRecord().Set("city", "上海").Set("street", "南京路"))
+
+	// 1. 操作人来自 context
+	fmt.Println("\n1. 操作人来自 context")
+	ctx := WithActor(context.Background(), "admin@example.com")
+	audited := auditor.Audit(ctx, user)
+	audited.Set("name", "李四").Set("vip", true).Delete("email")
+	fmt.Printf("   操作人: %s\n", audited.Actor())
+	fmt.Printf("   Record: %s\n", user.ToJson())
+
+	// 2. 嵌套 Record 和合并
+	fmt.Println("\n2. 嵌套 Record 和合并")
+	address, _ := audited.GetRecord("address")
+	address.Set("city", "北京")
+	audited.Merge(eorm.NewRecord().Set("id", 1).Set("name", "王五").Set("level", 3))
+	audited.FromMap(map[string]interface{}{"vip": false, "score": 88})
+	fmt.Printf("   Record: %s\n", user.ToJson())
+	fmt.Println("   Merge 中的 id 值没有变化，不记录")
+	audited.Set("level", 3.0)
+	fmt.Println("   level 从 int 3 改为 float64 3.0，类型变化会记录")
+
+	// 3. 没有操作人时使用 DefaultActor
+	fmt.Println("\n3. 没有操作人时使用 DefaultActor")
+	job := auditor.Audit(context.Background(), user)
+	job.Delete("score")
+	fmt.Printf("   操作人: %s\n", job.Actor())
+
+	// 4. Clear 为每一列记录一条
+	fmt.Println("\n4. Clear")
+	draft := eorm.NewRecord().Set("title", "草稿").Set("body", "内容")
+	auditor.Audit(ctx, draft).Clear()
+	fmt.Printf("   draft=%s\n", draft.ToJson())
+
+	// 5. 审计日志
+	fmt.Println("\n5. 审计日志")
+	entries := sink.Entries()
+	for _, e := range entries {
+		fmt.Printf("   %s %-18s %-6s %-14s %v -> %v\n", e.Time.Format("15:04:05"), e.Actor, e.Op, e.Path, e.Old, e.New)
+	}
+
+	// 6. 导出为 NDJSON
+	fmt.Println("\n6. 导出为 NDJSON")
+	var buf bytes.Buffer
+	_ = WriteNDJSON(&buf, entries[:3])
+	printIndented(&buf)
+
+	// 7. 导出为 Record
+	fmt.Println("\n7. 导出为 Record")
+	records := ToRecords(entries)
+	fmt.Printf("   %s\n", records[0].ToJson())
+	fmt.Printf("   %s\n", records[2].ToJson())
+	fmt.Printf(`   _, err := eorm.BatchInsertRecord("audit_log", records) // %d 条`+"\n", len(records))
+
+	// 8. 同时写入多个 Sink
+	fmt.Println("\n8. 同时写入多个 Sink")
+	buf.Reset()
+	both := &Auditor{Sink: MultiSink(sink, NewNDJSONSink(&buf)), Now: fakeClock()}
+	both.Audit(WithActor(context.Background(), "ops"), user).Set("level", 4)
+	printIndented(&buf)
+	fmt.Printf("   MemorySink 共 %d 条\n", len(sink.Entries()))
+
+	// 9. Sink 写入失败
+	fmt.Println("\n9. Sink 写入失败")
+	failing := &Auditor{Sink: SinkFunc(func(Entry) error { return errors.New("disk full") })}
+	a := failing.Audit(ctx, eorm.NewRecord().Set("id", 2))
+	if err := a.Set("name", "赵六").Err(); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	if _, err := audited.GetRecord("name"); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zzguang83325/eorm"
+)
+
+func show(c Change) string {
+	return fmt.Sprintf("%s %s: %v -> %v", c.Op, c.Path, display(c.Old), display(c.New))
+}
+
+func display(v interface{}) interface{} {
+	if rec, ok := v.(*eorm.Record); ok {
+		return rec.ToJson()
+	}
+	return v
+}
+
+// 示例37：变更通知
+// 演示按路径模式观察 Record 的变更，支持同步回调和 channel 两种方式，以及嵌套 Record 和取消观察
+func main() {
+	fmt.Println("========== 变更通知示例 ==========")
+
+	config := eorm.NewRecord().
+		Set("app", "shop").
+		Set("theme", "light").
+		Set("db", eorm.NewRecord().Set("host", "localhost").Set("port", 3306)).
+		Set("features", eorm.NewRecord().
+			Set("search", eorm.NewRecord().Set("enabled", true)).
+			Set("cart", eorm.NewRecord().Set("enabled", false)))
+	obs := Observe(config)
+
+	// 1. 同步回调
+	fmt.Println("\n1. 同步回调")
+	themeSub := obs.Watch("theme", func(c Change) {
+		fmt.Printf("   [theme] %s\n", show(c))
+	})
+	obs.Set("theme", "dark")
+	obs.Set("app", "shop-v2")
+	fmt.Println("   修改 app 不通知 theme 的观察者")
+
+	// 2. 通配符
+	fmt.Println("\n2. 通配符")
+	dbSub := obs.Watch("db.**", func(c Change) {
+		fmt.Printf("   [db.**] %s\n", show(c))
+	})
+	featureSub := obs.Watch("features.*.enabled", func(c Change) {
+		fmt.Printf("   [features.*.enabled] %s\n", show(c))
+	})
+	if err := obs.SetByPath("db.port", 3307); err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+	_ = obs.SetByPath("features.cart.enabled", true)
+	_ = obs.SetByPath("db.pool.max_open", 20)
+	dbRecord, _ := config.GetRecord("db")
+	fmt.Printf("   SetByPath 自动创建中间 Record: db=%s\n", dbRecord.ToJson())
+
+	// 3. 嵌套 Record
+	fmt.Println("\n3. 嵌套 Record")
+	db, _ := obs.GetRecord("db")
+	db.Set("host", "db.internal")
+	db.Watch("user", func(c Change) {
+		fmt.Printf("   [db 中注册的 user] %s\n", show(c))
+	})
+	obs.Merge(eorm.NewRecord().Set("db", eorm.NewRecord().Set("host", "10.0.0.1").Set("user", "app")))
+	fmt.Println("   整体替换 db 时，观察 db.** 和 db.user 的观察者都收到通知，路径为 db")
+
+	// 4. channel
+	fmt.Println("\n4. channel")
+	ch, chSub := obs.WatchChan("**", 2)
+	obs.FromMap(map[string]interface{}{"theme": "blue", "lang": "zh"})
+	obs.Delete("lang")
+	chSub.Unsubscribe()
+	for c := range ch {
+		fmt.Printf("   [chan] %s\n", show(c))
+	}
+	fmt.Printf("   channel 已满丢弃: %d\n", chSub.Dropped())
+
+	// 5. Clear
+	fmt.Println("\n5. Clear")
+	cart, _ := obs.GetRecord("features.cart")
+	cart.Clear()
+	features, _ := config.GetRecord("features")
+	fmt.Printf("   features=%s\n", features.ToJson())
+
+	// 6. 取消观察和 Clone
+	fmt.Println("\n6. 取消观察和 Clone")
+	fmt.Printf("   观察者: %d\n", obs.Watchers())
+	themeSub.Unsubscribe()
+	themeSub.Unsubscribe()
+	dbSub.Unsubscribe()
+	featureSub.Unsubscribe()
+	fmt.Printf("   取消后: %d\n", obs.Watchers())
+	copied := obs.Clone()
+	copied.Set("theme", "green")
+	fmt.Printf("   Clone 的观察者: %d，原 Record theme=%s，副本 theme=%s\n", copied.Watchers(), config.GetString("theme"), copied.Record().GetString("theme"))
+
+	// 7. 回调中继续修改
+	fmt.Println("\n7. 回调中继续修改")
+	obs.Watch("theme", func(c Change) {
+		obs.Set("theme_changed_at", "2024-05-01T10:30:00Z")
+	})
+	obs.Watch("theme_changed_at", func(c Change) {
+		fmt.Printf("   [theme_changed_at] %s\n", show(c))
+	})
+	obs.Set("theme", "light")
+
+	// 8. 错误处理
+	fmt.Println("\n8. 错误处理")
+	if err := obs.SetByPath("app.name", "x"); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	if err := obs.SetByPath("", "x"); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	if _, err := obs.GetRecord("missing"); err != nil {
+		fmt.Printf("   (预期): %v\n", err)
+	}
+	fmt.Println("   直接调用 config.Set 不会触发通知")
+
+	fmt.Println("\n========== 示例完成 ==========")
+}
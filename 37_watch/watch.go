@@ -0,0 +1,398 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zzguang83325/eorm"
+)
+
+// 变更的操作类型
+const (
+	OpSet    = "set"
+	OpDelete = "delete"
+	OpClear  = "clear"
+)
+
+// Change 描述一列的变更，Path 为从根 Record 开始的点分路径
+// Old、New 中的嵌套 Record 是变更时的深拷贝，统一为 *Record
+type Change struct {
+	Path string
+	Op   string
+	Old  interface{}
+	New  interface{}
+}
+
+// Observable 在 Record 之上提供变更通知，可以并发使用
+// 只有通过 Observable 的修改会触发通知；观察者保存在 Observable 中而不是 Record 中，
+// 因此 Record 的 Clone、DeepClone 不会复制观察者，也不会让观察者无法释放
+type Observable struct {
+	state *observeState
+	path  []string
+}
+
+type observeState struct {
+	mu     sync.Mutex // 保护 root 的修改
+	root   *eorm.Record
+	subsMu sync.Mutex
+	subs   []*Subscription
+	errMu  sync.Mutex
+	err    error
+}
+
+// Subscription 是一个观察者，Unsubscribe 后不再收到通知并从 Observable 中移除
+type Subscription struct {
+	state   *observeState
+	pattern []string
+	fn      func(Change)
+	ch      chan Change
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+}
+
+// Observe 开始观察 r 的变更
+func Observe(r *eorm.Record) *Observable {
+	return &Observable{state: &observeState{root: r}}
+}
+
+// Record 返回被观察的根 Record，用于读取
+func (o *Observable) Record() *eorm.Record {
+	return o.state.root
+}
+
+// Clone 返回包含 Record 深拷贝的新 Observable，不复制观察者
+func (o *Observable) Clone() *Observable {
+	o.state.mu.Lock()
+	defer o.state.mu.Unlock()
+	return Observe(o.state.root.DeepClone())
+}
+
+// Err 返回第一个失败的修改的错误，例如嵌套 Record 的路径已不存在
+func (o *Observable) Err() error {
+	o.state.errMu.Lock()
+	defer o.state.errMu.Unlock()
+	return o.state.err
+}
+
+// Watch 注册同步观察者，变更完成后在修改所在的 goroutine 中按注册顺序调用 fn
+// 调用 fn 时不持有锁，fn 中可以继续修改 Record
+//
+// pattern 为点分路径，相对于当前 Observable：
+// 1. "*" 匹配一段，例如 "items.*.qty"
+// 2. "**" 匹配零段或多段，例如 "address.**"
+// 3. 上层列被整体替换或删除时，观察下层路径的观察者也会收到通知，Change.Path 为上层列的路径
+func (o *Observable) Watch(pattern string, fn func(Change)) *Subscription {
+	return o.subscribe(pattern, fn, nil)
+}
+
+// WatchChan 注册通过 channel 接收变更的观察者，buffer 为 channel 容量
+// 发送不会阻塞修改：channel 已满时丢弃该变更并计入 Dropped；Unsubscribe 时关闭 channel
+func (o *Observable) WatchChan(pattern string, buffer int) (<-chan Change, *Subscription) {
+	ch := make(chan Change, buffer)
+	return ch, o.subscribe(pattern, nil, ch)
+}
+
+func (o *Observable) subscribe(pattern string, fn func(Change), ch chan Change) *Subscription {
+	full := append(append([]string(nil), o.path...), splitPath(pattern)...)
+	s := o.state
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	sub := &Subscription{state: s, pattern: full, fn: fn, ch: ch}
+	s.subs = append(s.subs, sub)
+	return sub
+}
+
+// Unsubscribe 移除观察者，可以重复调用
+func (sub *Subscription) Unsubscribe() {
+	s := sub.state
+	s.subsMu.Lock()
+	for i, other := range s.subs {
+		if other == sub {
+			s.subs = append(s.subs[:i:i], s.subs[i+1:]...)
+			break
+		}
+	}
+	s.subsMu.Unlock()
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if !sub.closed {
+		sub.closed = true
+		if sub.ch != nil {
+			close(sub.ch)
+		}
+	}
+}
+
+// Dropped 返回因 channel 已满而丢弃的变更数
+func (sub *Subscription) Dropped() int {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.dropped
+}
+
+// Watchers 返回当前的观察者数量
+func (o *Observable) Watchers() int {
+	o.state.subsMu.Lock()
+	defer o.state.subsMu.Unlock()
+	return len(o.state.subs)
+}
+
+func (sub *Subscription) deliver(c Change) {
+	if sub.fn != nil {
+		sub.mu.Lock()
+		closed := sub.closed
+		sub.mu.Unlock()
+		if !closed {
+			sub.fn(c)
+		}
+		return
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.ch <- c:
+	default:
+		sub.dropped++
+	}
+}
+
+// Set 设置列值
+func (o *Observable) Set(key string, value interface{}) *Observable {
+	o.modify(o.path, func(r *eorm.Record, emit func(key, op string, old, new interface{})) {
+		emit(key, OpSet, r.Get(key), value)
+		r.Set(key, value)
+	})
+	return o
+}
+
+// SetByPath 按点分路径设置值，例如 "address.geo.lat"，不存在的中间 Record 会自动创建
+// 创建了中间 Record 时，在第一个新建的列上通知一次，New 为新建的整个子树；路径为空或中间列存在但不是 Record 时返回错误且不做任何修改
+func (o *Observable) SetByPath(path string, value interface{}) error {
+	segs := splitPath(path)
+	if len(segs) == 0 {
+		return fmt.Errorf("watch: path cannot be empty")
+	}
+	full := append(append([]string(nil), o.path...), segs...)
+	parents, leaf := full[:len(full)-1], full[len(full)-1]
+
+	var err error
+	o.modify(nil, func(root *eorm.Record, emit func(key, op string, old, new interface{})) {
+		// 先找到第一个不存在的中间列，途中遇到不是 Record 的列时不做任何修改
+		created := len(parents)
+		for i := range parents {
+			var r *eorm.Record
+			if r, err = lookup(root, parents[:i]); err != nil {
+				return
+			}
+			if !r.Has(parents[i]) {
+				created = i
+				break
+			}
+		}
+		if created == len(parents) {
+			if _, err = lookup(root, parents); err != nil {
+				return
+			}
+			err = edit(root, parents, func(r *eorm.Record) {
+				emit(strings.Join(full, "."), OpSet, r.Get(leaf), value)
+				r.Set(leaf, value)
+			})
+			return
+		}
+		var node interface{} = value
+		for i := len(full) - 1; i > created; i-- {
+			node = eorm.NewRecord().Set(full[i], node)
+		}
+		err = edit(root, parents[:created], func(r *eorm.Record) {
+			emit(strings.Join(full[:created+1], "."), OpSet, nil, node)
+			r.Set(full[created], node)
+		})
+	})
+	return err
+}
+
+// Delete 删除列，列不存在时不通知
+func (o *Observable) Delete(key string) *Observable {
+	o.modify(o.path, func(r *eorm.Record, emit func(key, op string, old, new interface{})) {
+		if r.Has(key) {
+			emit(key, OpDelete, r.Get(key), nil)
+			r.Delete(key)
+		}
+	})
+	return o
+}
+
+// Clear 删除全部列，每一列通知一次
+func (o *Observable) Clear() *Observable {
+	o.modify(o.path, func(r *eorm.Record, emit func(key, op string, old, new interface{})) {
+		for _, key := range r.Keys() {
+			emit(key, OpClear, r.Get(key), nil)
+		}
+		r.Clear()
+	})
+	return o
+}
+
+// Merge 将 src 的列合并到 Record，src 中没有的列保持不变，每一列通知一次
+func (o *Observable) Merge(src *eorm.Record) *Observable {
+	if src == nil {
+		return o
+	}
+	o.modify(o.path, func(r *eorm.Record, emit func(key, op string, old, new interface{})) {
+		for _, key := range src.Keys() {
+			emit(key, OpSet, r.Get(key), src.Get(key))
+			r.Set(key, src.Get(key))
+		}
+	})
+	return o
+}
+
+// FromMap 按键名排序后逐列设置，每一列通知一次
+func (o *Observable) FromMap(m map[string]interface{}) *Observable {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	o.modify(o.path, func(r *eorm.Record, emit func(key, op string, old, new interface{})) {
+		for _, key := range keys {
+			emit(key, OpSet, r.Get(key), m[key])
+			r.Set(key, m[key])
+		}
+	})
+	return o
+}
+
+// GetRecord 返回嵌套 Record 的 Observable，通过它的修改和注册的观察者都使用带前缀的路径
+func (o *Observable) GetRecord(path string) (*Observable, error) {
+	full := append(append([]string(nil), o.path...), splitPath(path)...)
+	o.state.mu.Lock()
+	defer o.state.mu.Unlock()
+	if _, err := lookup(o.state.root, full); err != nil {
+		return nil, err
+	}
+	return &Observable{state: o.state, path: full}, nil
+}
+
+// modify 在 path 对应的 Record 上执行 fn，完成后释放锁再通知观察者
+func (o *Observable) modify(path []string, fn func(r *eorm.Record, emit func(key, op string, old, new interface{}))) error {
+	s := o.state
+	var changes []Change
+	prefix := strings.Join(path, ".")
+
+	s.mu.Lock()
+	err := edit(s.root, path, func(r *eorm.Record) {
+		fn(r, func(key, op string, old, new interface{}) {
+			p := key
+			if prefix != "" {
+				p = prefix + "." + key
+			}
+			changes = append(changes, Change{Path: p, Op: op, Old: recordPtr(old), New: recordPtr(new)})
+		})
+	})
+	s.mu.Unlock()
+	if err != nil {
+		s.errMu.Lock()
+		if s.err == nil {
+			s.err = err
+		}
+		s.errMu.Unlock()
+		return err
+	}
+
+	for _, c := range changes {
+		segs := splitPath(c.Path)
+		s.subsMu.Lock()
+		subs := append([]*Subscription(nil), s.subs...)
+		s.subsMu.Unlock()
+		for _, sub := range subs {
+			if matchPath(sub.pattern, segs) {
+				sub.deliver(c)
+			}
+		}
+	}
+	return nil
+}
+
+// recordPtr 深拷贝嵌套 Record，之后的修改不会改变已通知的值
+func recordPtr(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *eorm.Record:
+		return val.DeepClone()
+	case eorm.Record:
+		return (&val).DeepClone()
+	}
+	return v
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// matchPath 判断变更路径 path 是否与 pattern 匹配，path 为 pattern 可以匹配的某个路径的上层时也算匹配
+// 列名不区分大小写，与 Record 一致
+func matchPath(pattern, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	switch pattern[0] {
+	case "**":
+		return matchPath(pattern[1:], path) || matchPath(pattern, path[1:])
+	case "*":
+		return matchPath(pattern[1:], path[1:])
+	}
+	return strings.EqualFold(pattern[0], path[0]) && matchPath(pattern[1:], path[1:])
+}
+
+// edit 在 path 对应的嵌套 Record 上执行 fn，本身不通知观察者，由 modify 在释放锁后通知
+func edit(r *eorm.Record, path []string, fn func(r *eorm.Record)) error {
+	if len(path) == 0 {
+		fn(r)
+		return nil
+	}
+	key := path[0]
+	child, err := lookup(r, path[:1])
+	if err != nil {
+		return err
+	}
+	if err := edit(child, path[1:], fn); err != nil {
+		return err
+	}
+	if _, shared := r.Get(key).(*eorm.Record); !shared {
+		r.Set(key, child)
+	}
+	return nil
+}
+
+// lookup 取出嵌套 Record，GetRecord 和 SetByPath 用它检查路径
+func lookup(r *eorm.Record, path []string) (*eorm.Record, error) {
+	for i, key := range path {
+		switch raw := r.Get(key).(type) {
+		case nil:
+			return nil, fmt.Errorf("watch: '%s' not found", strings.Join(path[:i+1], "."))
+		case *eorm.Record, eorm.Record, map[string]interface{}:
+		default:
+			return nil, fmt.Errorf("watch: '%s' is %T, not a Record", strings.Join(path[:i+1], "."), raw)
+		}
+		child, err := r.GetRecord(key)
+		if err != nil {
+			return nil, fmt.Errorf("watch: %v", err)
+		}
+		r = child
+	}
+	return r, nil
+}
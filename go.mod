@@ -2,6 +2,9 @@ module examples/records
 
 go 1.24.0
 
-require github.com/zzguang83325/eorm v1.0.2
+require (
+	github.com/zzguang83325/eorm v1.0.2
+	google.golang.org/protobuf v1.36.9
+)
 
 require golang.org/x/text v0.14.0 // indirect